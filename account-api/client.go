@@ -3,13 +3,16 @@ package account_api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/haokeyingxiao/haoke-cli/internal/secret"
 	"github.com/haokeyingxiao/haoke-cli/logging"
 )
 
@@ -21,9 +24,20 @@ func SetUserAgent(userAgent string) {
 
 type Client struct {
 	Token token `json:"token"`
+	// ActiveCompany is the company whose producer is used, empty for the own company of the user
+	ActiveCompany string `json:"activeCompany,omitempty"`
+	profile       string
+	// credentials are used to login again when the token expires
+	credentials AccountConfig
 }
 
 func (c *Client) NewAuthenticatedRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	if !c.isTokenValid() {
+		if err := c.refreshToken(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	logging.FromContext(ctx).Debugf("%s: %s", method, path)
 	r, err := http.NewRequestWithContext(ctx, method, path, body)
 	if err != nil {
@@ -38,34 +52,68 @@ func (c *Client) NewAuthenticatedRequest(ctx context.Context, method, path strin
 	return r, nil
 }
 
-func (*Client) doRequest(request *http.Request) ([]byte, error) {
-	resp, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return nil, err
+func (c *Client) doRequest(request *http.Request) ([]byte, error) {
+	data, err := sendRequest(request)
+
+	// the token may be revoked before it expires, try once to login again
+	if errors.Is(err, ErrUnauthorized) && c.credentials != nil {
+		if refreshErr := c.refreshToken(request.Context()); refreshErr != nil {
+			return nil, err
+		}
+
+		retry := request.Clone(request.Context())
+		retry.Header.Set("X-Shopware-Platform-Token", c.Token.ShopUserToken.Token)
+
+		if request.GetBody != nil {
+			if retry.Body, err = request.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		return sendRequest(retry)
 	}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		_ = resp.Body.Close()
+	return data, err
+}
 
-		return nil, fmt.Errorf("doRequest: %v", err)
+// refreshToken logs in again with the stored credentials and updates the token cache.
+func (c *Client) refreshToken(ctx context.Context) error {
+	if c.credentials == nil || c.credentials.GetAccountEmail() == "" || c.credentials.GetAccountPassword() == "" {
+		return fmt.Errorf("the token has expired and no credentials are stored, please login again")
 	}
 
-	if err := resp.Body.Close(); err != nil {
-		return nil, fmt.Errorf("doRequest: %v", err)
+	logging.FromContext(ctx).Debugf("Token has expired, logging in again")
+
+	refreshed, err := login(ctx, LoginRequest{
+		Email:    c.credentials.GetAccountEmail(),
+		Password: c.credentials.GetAccountPassword(),
+	})
+	if err != nil {
+		return fmt.Errorf("refresh token: %w", err)
 	}
 
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf(string(data))
+	c.Token = refreshed.Token
+
+	if err := saveApiTokenToTokenCache(c); err != nil {
+		logging.FromContext(ctx).Errorf("Cannot save token cache: %v", err)
 	}
 
-	return data, nil
+	return nil
 }
 
 func (c *Client) GetUserID() string {
 	return c.Token.UserID
 }
 
+// GetActiveCompanyID returns the selected company or the own company of the user.
+func (c *Client) GetActiveCompanyID() string {
+	if c.ActiveCompany != "" {
+		return c.ActiveCompany
+	}
+
+	return c.GetUserID()
+}
+
 func (c *Client) isTokenValid() bool {
 	loc, err := time.LoadLocation(c.Token.Expire.Timezone)
 	if err != nil {
@@ -81,39 +129,38 @@ func (c *Client) isTokenValid() bool {
 	return expire.UTC().Sub(time.Now().UTC()).Seconds() > 60
 }
 
+// CacheFileName is the token cache of older versions, it is removed on logout.
 const CacheFileName = "haoke-api-client-token.json"
 
-func getApiTokenCacheFilePath() (string, error) {
-	cacheDir, err := os.UserCacheDir()
-	if err != nil {
-		return "", err
+// secretStore keeps the api tokens, one per account profile.
+var secretStore = secret.Default
+
+func tokenSecretKey(profile string) string {
+	if profile == "" {
+		profile = "default"
 	}
 
-	return fmt.Sprintf("%s/%s", cacheDir, CacheFileName), nil
+	return fmt.Sprintf("account-token-%s", profile)
 }
 
-func createApiFromTokenCache(ctx context.Context) (*Client, error) {
-	tokenFilePath, err := getApiTokenCacheFilePath()
+func createApiFromTokenCache(ctx context.Context, profile string) (*Client, error) {
+	store, err := secretStore()
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := os.Stat(tokenFilePath); os.IsNotExist(err) {
-		return nil, err
-	}
-
-	content, err := os.ReadFile(tokenFilePath)
+	content, err := store.Get(tokenSecretKey(profile))
 	if err != nil {
 		return nil, err
 	}
 
 	var client *Client
-	err = json.Unmarshal(content, &client)
+	err = json.Unmarshal([]byte(content), &client)
 	if err != nil {
 		return nil, err
 	}
 
-	logging.FromContext(ctx).Debugf("Using token cache from %s", tokenFilePath)
+	logging.FromContext(ctx).Debugf("Using token cache from %s", store.Name())
 
 	if !client.isTokenValid() {
 		return nil, fmt.Errorf("token is expired")
@@ -123,7 +170,7 @@ func createApiFromTokenCache(ctx context.Context) (*Client, error) {
 }
 
 func saveApiTokenToTokenCache(client *Client) error {
-	tokenFilePath, err := getApiTokenCacheFilePath()
+	store, err := secretStore()
 	if err != nil {
 		return err
 	}
@@ -133,31 +180,38 @@ func saveApiTokenToTokenCache(client *Client) error {
 		return err
 	}
 
-	tokenFileDirectory := filepath.Dir(tokenFilePath)
-	if _, err := os.Stat(tokenFileDirectory); os.IsNotExist(err) {
-		err := os.MkdirAll(tokenFileDirectory, 0o750)
-		if err != nil {
-			return err
-		}
-	}
+	return store.Set(tokenSecretKey(client.profile), string(content))
+}
 
-	err = os.WriteFile(tokenFilePath, content, os.ModePerm)
+func InvalidateTokenCache(profile string) error {
+	store, err := secretStore()
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if err := store.Delete(tokenSecretKey(profile)); err != nil && !errors.Is(err, secret.ErrNotFound) {
+		return err
+	}
+
+	return removeLegacyTokenCache(profile)
 }
 
-func InvalidateTokenCache() error {
-	tokenFilePath, err := getApiTokenCacheFilePath()
+// removeLegacyTokenCache deletes the world-readable token file written by older versions.
+func removeLegacyTokenCache(profile string) error {
+	cacheDir, err := os.UserCacheDir()
 	if err != nil {
 		return err
 	}
 
-	if _, err := os.Stat(tokenFilePath); os.IsNotExist(err) {
-		return nil
+	tokenFilePath := filepath.Join(cacheDir, CacheFileName)
+
+	if profile != "" && profile != "default" {
+		tokenFilePath = filepath.Join(cacheDir, fmt.Sprintf("%s-%s.json", strings.TrimSuffix(CacheFileName, ".json"), profile))
 	}
 
-	return os.Remove(tokenFilePath)
+	if err := os.Remove(tokenFilePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
 }
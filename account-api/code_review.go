@@ -0,0 +1,106 @@
+package account_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/system"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+const (
+	CodeReviewStatusPassed             = "passed"
+	CodeReviewStatusPassedWithWarnings = "passed-with-warnings"
+	CodeReviewStatusFailed             = "failed"
+	CodeReviewStatusTimeout            = "timeout"
+)
+
+// Exit codes of the cli for the code review states, passed reviews exit with 0.
+const (
+	CodeReviewExitPassedWithWarnings = 2
+	CodeReviewExitFailed             = 3
+	CodeReviewExitTimeout            = 4
+)
+
+const (
+	codeReviewMinPollInterval = 10 * time.Second
+	codeReviewMaxPollInterval = time.Minute
+)
+
+// CodeReviewReport is the machine-readable outcome of a code review.
+type CodeReviewReport struct {
+	Status      string              `json:"status"`
+	ExtensionId int                 `json:"extensionId"`
+	BinaryId    int                 `json:"binaryId"`
+	Summary     string              `json:"summary,omitempty"`
+	Result      *BinaryReviewResult `json:"result,omitempty"`
+}
+
+// WaitForCodeReviewReport waits until the code review triggered after knownReviews results has finished or the timeout is reached.
+func (e ProducerEndpoint) WaitForCodeReviewReport(ctx context.Context, extensionId, binaryId, knownReviews int, timeout time.Duration) (*CodeReviewReport, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	report := &CodeReviewReport{ExtensionId: extensionId, BinaryId: binaryId}
+
+	review, err := e.WaitForCodeReview(waitCtx, extensionId, binaryId, knownReviews, codeReviewMinPollInterval, codeReviewMaxPollInterval)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		report.Status = CodeReviewStatusTimeout
+		report.Summary = fmt.Sprintf("code review did not finish within %s", timeout)
+
+		return report, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	report.Result = review
+	report.Summary = review.GetSummary()
+
+	switch {
+	case !review.HasPassed():
+		report.Status = CodeReviewStatusFailed
+	case review.HasWarnings():
+		report.Status = CodeReviewStatusPassedWithWarnings
+	default:
+		report.Status = CodeReviewStatusPassed
+	}
+
+	return report, nil
+}
+
+// FinishCodeReview writes the report to the output file (- for stdout) and returns an error with the exit code of the review status.
+func FinishCodeReview(ctx context.Context, report *CodeReviewReport, outputFile string) error {
+	if outputFile != "" {
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if outputFile == "-" {
+			fmt.Println(string(content))
+		} else if err := os.WriteFile(outputFile, content, os.ModePerm); err != nil {
+			return fmt.Errorf("cannot write code review result: %w", err)
+		}
+	}
+
+	switch report.Status {
+	case CodeReviewStatusPassed:
+		logging.FromContext(ctx).Infof("Code review has been passed without warnings")
+
+		return nil
+	case CodeReviewStatusPassedWithWarnings:
+		logging.FromContext(ctx).Info(report.Summary)
+
+		return &system.ExitError{Code: CodeReviewExitPassedWithWarnings, Err: fmt.Errorf("code review has been passed but with warnings")}
+	case CodeReviewStatusTimeout:
+		return &system.ExitError{Code: CodeReviewExitTimeout, Err: errors.New(report.Summary)}
+	}
+
+	return &system.ExitError{Code: CodeReviewExitFailed, Err: fmt.Errorf("code review has not passed: %s", report.Summary)}
+}
@@ -0,0 +1,50 @@
+package account_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/system"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+func TestFinishCodeReviewExitCodes(t *testing.T) {
+	ctx := logging.DisableLogger(context.Background())
+
+	assert.NoError(t, FinishCodeReview(ctx, &CodeReviewReport{Status: CodeReviewStatusPassed}, ""))
+
+	cases := map[string]int{
+		CodeReviewStatusPassedWithWarnings: CodeReviewExitPassedWithWarnings,
+		CodeReviewStatusFailed:             CodeReviewExitFailed,
+		CodeReviewStatusTimeout:            CodeReviewExitTimeout,
+	}
+
+	for status, code := range cases {
+		err := FinishCodeReview(ctx, &CodeReviewReport{Status: status, Summary: "summary"}, "")
+
+		var exitErr *system.ExitError
+		assert.True(t, errors.As(err, &exitErr), status)
+		assert.Equal(t, code, exitErr.Code, status)
+	}
+}
+
+func TestFinishCodeReviewWritesReport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "review.json")
+
+	report := &CodeReviewReport{Status: CodeReviewStatusTimeout, ExtensionId: 1, BinaryId: 2, Summary: "timeout"}
+
+	assert.Error(t, FinishCodeReview(logging.DisableLogger(context.Background()), report, file))
+
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+
+	var written CodeReviewReport
+	assert.NoError(t, json.Unmarshal(content, &written))
+	assert.Equal(t, *report, written)
+}
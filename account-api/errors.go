@@ -0,0 +1,188 @@
+package account_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnauthorized is matched by errors of requests rejected with 401
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is matched by errors of requests rejected with 404
+	ErrNotFound = errors.New("not found")
+)
+
+// ResponseError is returned for every response of the account api with a status code of 400 or above.
+type ResponseError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Code       string
+	Message    string
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Body
+	}
+
+	if e.Code != "" {
+		message = fmt.Sprintf("%s (%s)", message, e.Code)
+	}
+
+	return fmt.Sprintf("%s %s failed with status %d: %s", e.Method, e.URL, e.StatusCode, message)
+}
+
+func (e *ResponseError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+
+	return false
+}
+
+// FieldError describes a rejected field of the request payload.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError is returned when the account api rejected the request payload.
+type ValidationError struct {
+	ResponseError
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+
+	for _, field := range e.Fields {
+		if field.Field == "" {
+			fields = append(fields, field.Message)
+		} else {
+			fields = append(fields, fmt.Sprintf("%s: %s", field.Field, field.Message))
+		}
+	}
+
+	return fmt.Sprintf("%s [%s]", e.ResponseError.Error(), strings.Join(fields, ", "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return &e.ResponseError
+}
+
+type errorResponse struct {
+	Code        json.RawMessage `json:"code"`
+	Message     string          `json:"message"`
+	Detail      string          `json:"detail"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Errors      json.RawMessage `json:"errors"`
+}
+
+type errorResponseField struct {
+	Field        string `json:"field"`
+	Property     string `json:"property"`
+	PropertyPath string `json:"propertyPath"`
+	Source       struct {
+		Pointer string `json:"pointer"`
+	} `json:"source"`
+	Message string `json:"message"`
+	Detail  string `json:"detail"`
+	Title   string `json:"title"`
+}
+
+// newResponseError parses the error body of the account api, unknown bodies are kept as message.
+func newResponseError(request *http.Request, statusCode int, body []byte) error {
+	responseErr := ResponseError{
+		StatusCode: statusCode,
+		Method:     request.Method,
+		URL:        request.URL.Redacted(),
+		Body:       strings.TrimSpace(string(body)),
+	}
+
+	var parsed errorResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return &responseErr
+	}
+
+	responseErr.Code = strings.Trim(string(parsed.Code), `"`)
+	responseErr.Message = firstNonEmpty(parsed.Message, parsed.Detail, parsed.Description, parsed.Title)
+
+	fields := parseFieldErrors(parsed.Errors)
+
+	if len(fields) == 0 && statusCode != http.StatusUnprocessableEntity {
+		return &responseErr
+	}
+
+	return &ValidationError{ResponseError: responseErr, Fields: fields}
+}
+
+// parseFieldErrors supports a list of error objects and a map of field names to messages.
+func parseFieldErrors(raw json.RawMessage) []FieldError {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var list []errorResponseField
+	if err := json.Unmarshal(raw, &list); err == nil {
+		fields := make([]FieldError, 0, len(list))
+
+		for _, entry := range list {
+			fields = append(fields, FieldError{
+				Field:   firstNonEmpty(entry.PropertyPath, entry.Field, entry.Property, strings.TrimPrefix(entry.Source.Pointer, "/")),
+				Message: firstNonEmpty(entry.Message, entry.Detail, entry.Title),
+			})
+		}
+
+		return fields
+	}
+
+	var byField map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &byField); err != nil {
+		return nil
+	}
+
+	fields := make([]FieldError, 0, len(byField))
+
+	for field, value := range byField {
+		var messages []string
+		if err := json.Unmarshal(value, &messages); err != nil {
+			var message string
+			if err := json.Unmarshal(value, &message); err != nil {
+				continue
+			}
+
+			messages = []string{message}
+		}
+
+		for _, message := range messages {
+			fields = append(fields, FieldError{Field: field, Message: message})
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Field < fields[j].Field
+	})
+
+	return fields
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,260 @@
+package account_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+// HTTPOptions configures the http client used for all account api requests.
+type HTTPOptions struct {
+	// Timeout limits a single request including reading the response. Uploads are exempt as their duration
+	// depends on the file size, for them only the wait for the response headers is limited.
+	Timeout time.Duration
+	// Retries is the amount of additional attempts after a rate limit, server or network error
+	Retries    int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+const traceBodyLimit = 4096
+
+var (
+	httpOptions = DefaultHTTPOptions()
+	httpClient  = newHTTPClient(httpOptions)
+
+	redactedHeaders = []string{"X-Shopware-Platform-Token", "Authorization", "Cookie", "Set-Cookie"}
+	redactedFields  = regexp.MustCompile(`("(?:password|token|shopwareId)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+func DefaultHTTPOptions() HTTPOptions {
+	return HTTPOptions{
+		Timeout:    2 * time.Minute,
+		Retries:    3,
+		MinBackoff: 500 * time.Millisecond,
+		MaxBackoff: 10 * time.Second,
+	}
+}
+
+func SetHTTPOptions(options HTTPOptions) {
+	httpOptions = options
+	httpClient = newHTTPClient(options)
+}
+
+func newHTTPClient(options HTTPOptions) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: options.Timeout,
+			IdleConnTimeout:       90 * time.Second,
+			MaxIdleConns:          10,
+		},
+	}
+}
+
+// sendRequest executes the request with retries and returns the body of a successful response.
+// Rate limited requests are always retried, server and network errors only for idempotent methods.
+func sendRequest(request *http.Request) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && request.Body != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			request.Body = body
+		}
+
+		data, statusCode, retryAfter, err := sendOnce(request)
+
+		if !shouldRetry(request, statusCode, err) || attempt >= httpOptions.Retries {
+			return data, err
+		}
+
+		if request.Body != nil && request.GetBody == nil {
+			return data, fmt.Errorf("cannot retry %s %s without a replayable body: %w", request.Method, request.URL.Redacted(), err)
+		}
+
+		wait := backoff(attempt, retryAfter)
+
+		logging.FromContext(request.Context()).Debugf("Retrying %s %s in %s: %v", request.Method, request.URL.Redacted(), wait, err)
+
+		select {
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func sendOnce(request *http.Request) ([]byte, int, string, error) {
+	traceRequest(request)
+
+	start := time.Now()
+
+	if !isUpload(request) && httpOptions.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(request.Context(), httpOptions.Timeout)
+		defer cancel()
+
+		request = request.WithContext(ctx)
+	}
+
+	resp, err := httpClient.Do(request)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if closeErr := resp.Body.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, resp.StatusCode, "", fmt.Errorf("read response: %w", err)
+	}
+
+	traceResponse(request, resp, data, time.Since(start))
+
+	if resp.StatusCode >= 400 {
+		return nil, resp.StatusCode, resp.Header.Get("Retry-After"), newResponseError(request, resp.StatusCode, data)
+	}
+
+	return data, resp.StatusCode, "", nil
+}
+
+// isUpload reports whether the request sends a file, these are not limited by the request timeout.
+func isUpload(request *http.Request) bool {
+	return strings.HasPrefix(request.Header.Get("content-type"), "multipart/")
+}
+
+func shouldRetry(request *http.Request, statusCode int, err error) bool {
+	if err == nil || request.Context().Err() != nil {
+		return false
+	}
+
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+	default:
+		return false
+	}
+
+	if statusCode == 0 {
+		var netErr net.Error
+		return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+	}
+
+	switch statusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// backoff doubles the wait time per attempt with jitter, a Retry-After header of the server takes precedence.
+func backoff(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return min(time.Duration(seconds)*time.Second, httpOptions.MaxBackoff)
+		}
+
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return min(max(time.Until(date), 0), httpOptions.MaxBackoff)
+		}
+	}
+
+	wait := httpOptions.MinBackoff << attempt
+	if wait <= 0 || wait > httpOptions.MaxBackoff {
+		wait = httpOptions.MaxBackoff
+	}
+
+	//nolint:gosec
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+func traceEnabled(request *http.Request) bool {
+	return logging.FromContext(request.Context()).Desugar().Core().Enabled(zapcore.DebugLevel)
+}
+
+func traceRequest(request *http.Request) {
+	if !traceEnabled(request) {
+		return
+	}
+
+	body := ""
+
+	if request.GetBody != nil {
+		if reader, err := request.GetBody(); err == nil {
+			data, _ := io.ReadAll(reader)
+			body = traceBody(request.Header.Get("content-type"), data)
+		}
+	}
+
+	logging.FromContext(request.Context()).Debugf("--> %s %s\n%s%s", request.Method, request.URL.Redacted(), traceHeaders(request.Header), body)
+}
+
+func traceResponse(request *http.Request, resp *http.Response, data []byte, duration time.Duration) {
+	if !traceEnabled(request) {
+		return
+	}
+
+	logging.FromContext(request.Context()).Debugf("<-- %d %s %s (%s)\n%s%s", resp.StatusCode, request.Method, request.URL.Redacted(), duration.Round(time.Millisecond), traceHeaders(resp.Header), traceBody(resp.Header.Get("content-type"), data))
+}
+
+func traceHeaders(headers http.Header) string {
+	headers = headers.Clone()
+
+	for _, name := range redactedHeaders {
+		if headers.Get(name) != "" {
+			headers.Set(name, "***")
+		}
+	}
+
+	var b strings.Builder
+	_ = headers.Write(&b)
+
+	return b.String()
+}
+
+// traceBody returns json bodies with redacted secrets, other content is only described.
+func traceBody(contentType string, data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	if contentType != "" && !strings.Contains(contentType, "json") {
+		return fmt.Sprintf("<%d bytes of %s>", len(data), contentType)
+	}
+
+	body := redactBody(data)
+
+	if len(body) > traceBodyLimit {
+		body = body[:traceBodyLimit] + "..."
+	}
+
+	return body
+}
+
+func redactBody(data []byte) string {
+	return redactedFields.ReplaceAllString(string(data), `$1"***"`)
+}
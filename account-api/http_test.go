@@ -0,0 +1,167 @@
+package account_api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func useTestHTTPOptions(t *testing.T) {
+	t.Helper()
+
+	SetHTTPOptions(HTTPOptions{Timeout: time.Second, Retries: 2, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+
+	t.Cleanup(func() {
+		SetHTTPOptions(DefaultHTTPOptions())
+	})
+}
+
+func TestSendRequestRetries(t *testing.T) {
+	useTestHTTPOptions(t)
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		body := make([]byte, 4)
+		_, _ = r.Body.Read(body)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, strings.NewReader("test"))
+	assert.NoError(t, err)
+
+	data, err := sendRequest(request)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", string(data))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestSendRequestDoesNotRetryPostOnServerError(t *testing.T) {
+	useTestHTTPOptions(t)
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	assert.NoError(t, err)
+
+	_, err = sendRequest(request)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	request, err = http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	_, err = sendRequest(request)
+	assert.Error(t, err)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestSendRequestKeepsErrorWithoutReplayableBody(t *testing.T) {
+	useTestHTTPOptions(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, io.NopCloser(strings.NewReader("test")))
+	assert.NoError(t, err)
+
+	_, err = sendRequest(request)
+	assert.ErrorContains(t, err, "without a replayable body")
+
+	var responseErr *ResponseError
+	assert.True(t, errors.As(err, &responseErr))
+	assert.Equal(t, http.StatusTooManyRequests, responseErr.StatusCode)
+}
+
+func TestSendRequestTimeoutExemptsUploads(t *testing.T) {
+	SetHTTPOptions(HTTPOptions{Timeout: 50 * time.Millisecond, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	t.Cleanup(func() {
+		SetHTTPOptions(DefaultHTTPOptions())
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		time.Sleep(150 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	}))
+	defer server.Close()
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	_, err = sendRequest(request)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	request, err = http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, strings.NewReader("file"))
+	assert.NoError(t, err)
+	request.Header.Set("content-type", "multipart/form-data; boundary=test")
+
+	data, err := sendRequest(request)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", string(data))
+}
+
+func TestResponseErrors(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPut, "https://api.example.com/plugins/1", nil)
+
+	err := newResponseError(request, http.StatusNotFound, []byte(`{"code":"PluginsException-1","detail":"Plugin not found"}`))
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrUnauthorized)
+	assert.Equal(t, "PUT https://api.example.com/plugins/1 failed with status 404: Plugin not found (PluginsException-1)", err.Error())
+
+	err = newResponseError(request, http.StatusUnauthorized, []byte("denied"))
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.Contains(t, err.Error(), "denied")
+
+	err = newResponseError(request, http.StatusBadRequest, []byte(`{"message":"Invalid","errors":[{"propertyPath":"name","message":"must not be empty"}]}`))
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldError{{Field: "name", Message: "must not be empty"}}, validationErr.Fields)
+	assert.Contains(t, err.Error(), "[name: must not be empty]")
+
+	var responseErr *ResponseError
+	assert.True(t, errors.As(err, &responseErr))
+	assert.Equal(t, http.StatusBadRequest, responseErr.StatusCode)
+
+	err = newResponseError(request, http.StatusUnprocessableEntity, []byte(`{"errors":{"version":["is invalid","is used"]}}`))
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.Fields, 2)
+}
+
+func TestTraceRedactsSecrets(t *testing.T) {
+	assert.Equal(t, `{"shopwareId":"***","password":"***","name":"test"}`, redactBody([]byte(`{"shopwareId":"a@b.c","password":"p\"w","name":"test"}`)))
+	assert.Equal(t, "<10 bytes of application/zip>", traceBody("application/zip", make([]byte, 10)))
+
+	headers := http.Header{}
+	headers.Set("X-Shopware-Platform-Token", "secret")
+	assert.NotContains(t, traceHeaders(headers), "secret")
+	assert.Equal(t, "secret", headers.Get("X-Shopware-Platform-Token"))
+}
@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/haokeyingxiao/haoke-cli/logging"
-	"io"
 	"net/http"
 )
 
@@ -15,21 +15,49 @@ const ApiUrl = "https://api.haokeyingxiao.com"
 type AccountConfig interface {
 	GetAccountEmail() string
 	GetAccountPassword() string
+	GetAccountProfile() string
+	GetAccountCompany() string
 }
 
+// NewApi returns a client for the account profile of the config, the token is cached per profile.
 func NewApi(ctx context.Context, config AccountConfig) (*Client, error) {
-	errorFormat := "login: %v"
+	profile := config.GetAccountProfile()
+	company := config.GetAccountCompany()
 
-	request := LoginRequest{
-		Email:    config.GetAccountEmail(),
-		Password: config.GetAccountPassword(),
+	client, err := createApiFromTokenCache(ctx, profile)
+	if err != nil {
+		client, err = login(ctx, LoginRequest{
+			Email:    config.GetAccountEmail(),
+			Password: config.GetAccountPassword(),
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
-	client, err := createApiFromTokenCache(ctx)
 
-	if err == nil {
-		return client, nil
+	client.profile = profile
+	client.credentials = config
+
+	if client.ActiveCompany != company {
+		if company == "" {
+			client.ActiveCompany = ""
+		} else if err := client.UseCompany(ctx, company); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := saveApiTokenToTokenCache(client); err != nil {
+		logging.FromContext(ctx).Errorf(fmt.Sprintf("Cannot save token cache: %v", err))
+	} else if err := removeLegacyTokenCache(profile); err != nil {
+		logging.FromContext(ctx).Debugf("Cannot remove old token cache: %v", err)
 	}
 
+	return client, nil
+}
+
+func login(ctx context.Context, request LoginRequest) (*Client, error) {
+	errorFormat := "login: %w"
+
 	s, err := json.Marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
@@ -41,42 +69,27 @@ func NewApi(ctx context.Context, config AccountConfig) (*Client, error) {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("user-agent", httpUserAgent)
 
-	resp, err := http.DefaultClient.Do(req)
+	data, err := sendRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf(errorFormat, err)
-	}
-
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			logging.FromContext(ctx).Errorf("Cannot close response body: %v", err)
+		var responseErr *ResponseError
+		if errors.As(err, &responseErr) && responseErr.StatusCode < 500 && responseErr.StatusCode != 429 {
+			logging.FromContext(ctx).Debugf("Login failed with response: %s", responseErr.Body)
+			return nil, fmt.Errorf("login failed. Check your credentials: %w", err)
 		}
-	}()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	if resp.StatusCode != 200 {
-		logging.FromContext(ctx).Debugf("Login failed with response: %s", string(data))
-		return nil, fmt.Errorf("login failed. Check your credentials")
-	}
-
 	var token token
 	if err := json.Unmarshal(data, &token); err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	client = &Client{
+	return &Client{
 		Token: token,
-	}
-
-	if err := saveApiTokenToTokenCache(client); err != nil {
-		logging.FromContext(ctx).Errorf(fmt.Sprintf("Cannot token cache: %v", err))
-	}
-
-	return client, nil
+	}, nil
 }
 
 type token struct {
@@ -97,6 +110,8 @@ type tokenExpire struct {
 type LoginRequest struct {
 	Email    string `json:"shopwareId"`
 	Password string `json:"password"`
+	Profile  string `json:"-"`
+	Company  string `json:"-"`
 }
 
 func (l LoginRequest) GetAccountEmail() string {
@@ -106,3 +121,11 @@ func (l LoginRequest) GetAccountEmail() string {
 func (l LoginRequest) GetAccountPassword() string {
 	return l.Password
 }
+
+func (l LoginRequest) GetAccountProfile() string {
+	return l.Profile
+}
+
+func (l LoginRequest) GetAccountCompany() string {
+	return l.Company
+}
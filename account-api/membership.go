@@ -0,0 +1,85 @@
+package account_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type Membership struct {
+	Id      string `json:"id"`
+	Active  bool   `json:"active"`
+	Company struct {
+		Id             string `json:"id"`
+		Name           string `json:"name"`
+		CustomerNumber string `json:"customerNumber"`
+	} `json:"company"`
+	Roles []struct {
+		Name string `json:"name"`
+	} `json:"roles"`
+}
+
+func (m Membership) GetRoles() []string {
+	roles := make([]string, 0, len(m.Roles))
+
+	for _, role := range m.Roles {
+		roles = append(roles, role.Name)
+	}
+
+	return roles
+}
+
+// GetMemberships returns the companies the user is a member of.
+func (c *Client) GetMemberships(ctx context.Context) ([]Membership, error) {
+	errorFormat := "GetMemberships: %w"
+
+	r, err := c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/swplatform/account/%s/memberships", ApiUrl, c.GetUserID()), nil)
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	body, err := c.doRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	var memberships []Membership
+	if err := json.Unmarshal(body, &memberships); err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	return memberships, nil
+}
+
+// UseCompany selects the company by id, customer number or name for all further producer requests.
+func (c *Client) UseCompany(ctx context.Context, company string) error {
+	memberships, err := c.GetMemberships(ctx)
+	if err != nil {
+		return err
+	}
+
+	membership, err := FindMembership(memberships, company)
+	if err != nil {
+		return err
+	}
+
+	c.ActiveCompany = membership.Company.Id
+
+	return nil
+}
+
+// FindMembership returns the membership of the company matching id, customer number or name.
+func FindMembership(memberships []Membership, company string) (*Membership, error) {
+	names := make([]string, 0, len(memberships))
+
+	for i, membership := range memberships {
+		if membership.Company.Id == company || membership.Company.CustomerNumber == company || strings.EqualFold(membership.Company.Name, company) {
+			return &memberships[i], nil
+		}
+
+		names = append(names, membership.Company.Name)
+	}
+
+	return nil, fmt.Errorf("you are not a member of company %s, available companies: %s", company, strings.Join(names, ", "))
+}
@@ -20,7 +20,7 @@ func (e ProducerEndpoint) GetId() string {
 	return e.producerId
 }
 func (c *Client) Producer(ctx context.Context) (*ProducerEndpoint, error) {
-	r, err := c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers/%s", ApiUrl, c.GetUserID()), nil)
+	r, err := c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers/%s", ApiUrl, c.GetActiveCompanyID()), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +32,7 @@ func (c *Client) Producer(ctx context.Context) (*ProducerEndpoint, error) {
 
 	var allocation companyAllocation
 	if err := json.Unmarshal(body, &allocation); err != nil {
-		return nil, fmt.Errorf("producer.profile: %v", err)
+		return nil, fmt.Errorf("producer.profile: %w", err)
 	}
 
 	if !allocation.IsProducer {
@@ -60,7 +60,7 @@ func (e ProducerEndpoint) Profile(ctx context.Context) (*Producer, error) {
 
 	var producers []Producer
 	if err := json.Unmarshal(body, &producers); err != nil {
-		return nil, fmt.Errorf("my_profile: %v", err)
+		return nil, fmt.Errorf("my_profile: %w", err)
 	}
 
 	for _, profile := range producers {
@@ -91,7 +91,7 @@ func (e ProducerEndpoint) Extensions(ctx context.Context, criteria *ListExtensio
 	form.Set("producerId", e.GetId())
 	err := encoder.Encode(criteria, form)
 	if err != nil {
-		return nil, fmt.Errorf("list_extensions: %v", err)
+		return nil, fmt.Errorf("list_extensions: %w", err)
 	}
 
 	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins?%s", ApiUrl, form.Encode()), nil)
@@ -106,7 +106,7 @@ func (e ProducerEndpoint) Extensions(ctx context.Context, criteria *ListExtensio
 
 	var extensions []Extension
 	if err := json.Unmarshal(body, &extensions); err != nil {
-		return nil, fmt.Errorf("list_extensions: %v", err)
+		return nil, fmt.Errorf("list_extensions: %w", err)
 	}
 
 	return extensions, nil
@@ -132,7 +132,7 @@ func (e ProducerEndpoint) GetExtensionByName(ctx context.Context, name string) (
 }
 
 func (e ProducerEndpoint) GetExtensionById(ctx context.Context, id int) (*Extension, error) {
-	errorFormat := "GetExtensionById: %v"
+	errorFormat := "GetExtensionById: %w"
 
 	// Create it
 	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins/%d", ApiUrl, id), nil)
@@ -154,24 +154,12 @@ func (e ProducerEndpoint) GetExtensionById(ctx context.Context, id int) (*Extens
 }
 
 type Extension struct {
-	Id             int    `json:"id"`
-	ProducerId     string `json:"producerId"`
-	Type           string `json:"type"`
-	Name           string `json:"name"`
-	StandardLocale Locale `json:"standardLocale"`
-	Infos          []*struct {
-		Id                 int          `json:"id"`
-		Locale             Locale       `json:"locale"`
-		Name               string       `json:"name"`
-		Description        string       `json:"description"`
-		InstallationManual string       `json:"installationManual"`
-		ShortDescription   string       `json:"shortDescription"`
-		Highlights         string       `json:"highlights"`
-		Features           string       `json:"features"`
-		Tags               []StoreTag   `json:"tags"`
-		Videos             []StoreVideo `json:"videos"`
-		Faqs               []StoreFaq   `json:"faqs"`
-	} `json:"infos"`
+	Id                                  int               `json:"id"`
+	ProducerId                          string            `json:"producerId"`
+	Type                                string            `json:"type"`
+	Name                                string            `json:"name"`
+	StandardLocale                      Locale            `json:"standardLocale"`
+	Infos                               []*ExtensionInfo  `json:"infos"`
 	PriceModels                         *StorePrice       `json:"priceModels"`
 	Variants                            []interface{}     `json:"variants"`
 	Categories                          []StoreCategory   `json:"categories"`
@@ -186,6 +174,20 @@ type Extension struct {
 	IsCompatibleWithLatestShopwareVersion bool   `json:"isCompatibleWithLatestShopwareVersion"`
 }
 
+type ExtensionInfo struct {
+	Id                 int          `json:"id"`
+	Locale             Locale       `json:"locale"`
+	Name               string       `json:"name"`
+	Description        string       `json:"description"`
+	InstallationManual string       `json:"installationManual"`
+	ShortDescription   string       `json:"shortDescription"`
+	Highlights         string       `json:"highlights"`
+	Features           string       `json:"features"`
+	Tags               []StoreTag   `json:"tags"`
+	Videos             []StoreVideo `json:"videos"`
+	Faqs               []StoreFaq   `json:"faqs"`
+}
+
 type StorePrice struct {
 	Type  string  `json:"type"`
 	Money float32 `json:"money"`
@@ -224,7 +226,7 @@ func (e ProducerEndpoint) CreateExtension(ctx context.Context, newExtension Crea
 
 	var extension Extension
 	if err := json.Unmarshal(body, &extension); err != nil {
-		return nil, fmt.Errorf("create_extension: %v", err)
+		return nil, fmt.Errorf("create_extension: %w", err)
 	}
 	return &extension, nil
 }
@@ -258,7 +260,7 @@ func (e ProducerEndpoint) DeleteExtension(ctx context.Context, id string) error
 }
 
 func (e ProducerEndpoint) GetSoftwareVersions(ctx context.Context, generation string) (*SoftwareVersionList, error) {
-	errorFormat := "shopware_versions: %v"
+	errorFormat := "shopware_versions: %w"
 	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/pluginstatics/softwareVersions?filter=[{\"property\":\"pluginGeneration\",\"value\":\"%s\"},{\"property\":\"includeNonPublic\",\"value\":\"1\"}]", ApiUrl, generation), nil)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
@@ -368,12 +370,12 @@ type ExtensionGeneralInformation struct {
 func (e ProducerEndpoint) GetExtensionGeneralInfo(ctx context.Context) (*ExtensionGeneralInformation, error) {
 	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/pluginstatics/all", ApiUrl), nil)
 	if err != nil {
-		return nil, fmt.Errorf("GetExtensionGeneralInfo: %v", err)
+		return nil, fmt.Errorf("GetExtensionGeneralInfo: %w", err)
 	}
 
 	body, err := e.c.doRequest(r)
 	if err != nil {
-		return nil, fmt.Errorf("GetExtensionGeneralInfo: %v", err)
+		return nil, fmt.Errorf("GetExtensionGeneralInfo: %w", err)
 	}
 
 	var info *ExtensionGeneralInformation
@@ -381,7 +383,7 @@ func (e ProducerEndpoint) GetExtensionGeneralInfo(ctx context.Context) (*Extensi
 	err = json.Unmarshal(body, &info)
 
 	if err != nil {
-		return nil, fmt.Errorf("shopware_versions: %v", err)
+		return nil, fmt.Errorf("shopware_versions: %w", err)
 	}
 
 	return info, nil
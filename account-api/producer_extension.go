@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/microcosm-cc/bluemonday"
 
@@ -25,6 +27,10 @@ type ExtensionBinary struct {
 	CompatibleSoftwareVersions SoftwareVersionList `json:"compatibleSoftwareVersions"`
 	CreationDate               string              `json:"creationDate"`
 	LastChangeDate             string              `json:"lastChangeDate"`
+	Changelogs                 []struct {
+		Locale Locale `json:"locale"`
+		Text   string `json:"text"`
+	} `json:"changelogs"`
 }
 
 type ExtensionUpdate struct {
@@ -40,6 +46,15 @@ type ExtensionUpdateChangelog struct {
 	Text   string `json:"text"`
 }
 
+// BinaryChangelogs converts the changelog of an extension into the changelogs of a binary.
+func BinaryChangelogs(german, english, chinese string) []ExtensionUpdateChangelog {
+	return []ExtensionUpdateChangelog{
+		{Locale: "de_DE", Text: german},
+		{Locale: "en_GB", Text: english},
+		{Locale: "zh_CN", Text: chinese},
+	}
+}
+
 type ExtensionCreate struct {
 	SoftwareVersions []string                   `json:"softwareVersions"`
 	Changelogs       []ExtensionUpdateChangelog `json:"changelogs"`
@@ -47,7 +62,7 @@ type ExtensionCreate struct {
 }
 
 func (e ProducerEndpoint) GetExtensionBinaries(ctx context.Context, extensionId int) ([]*ExtensionBinary, error) {
-	errorFormat := "GetExtensionBinaries: %v"
+	errorFormat := "GetExtensionBinaries: %w"
 
 	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers/%s/plugins/%d/binaries", ApiUrl, e.producerId, extensionId), nil)
 	if err != nil {
@@ -68,7 +83,7 @@ func (e ProducerEndpoint) GetExtensionBinaries(ctx context.Context, extensionId
 }
 
 func (e ProducerEndpoint) UpdateExtensionBinaryInfo(ctx context.Context, extensionId int, update ExtensionUpdate) error {
-	errorFormat := "UpdateExtensionBinaryInfo: %v"
+	errorFormat := "UpdateExtensionBinaryInfo: %w"
 
 	content, err := json.Marshal(update)
 	if err != nil {
@@ -86,7 +101,7 @@ func (e ProducerEndpoint) UpdateExtensionBinaryInfo(ctx context.Context, extensi
 }
 
 func (e ProducerEndpoint) CreateExtensionBinary(ctx context.Context, extensionId int, create ExtensionCreate) (*ExtensionBinary, error) {
-	errorFormat := "CreateExtensionBinary: %v"
+	errorFormat := "CreateExtensionBinary: %w"
 
 	createPayload, err := json.Marshal(create)
 	if err != nil {
@@ -112,7 +127,7 @@ func (e ProducerEndpoint) CreateExtensionBinary(ctx context.Context, extensionId
 }
 
 func (e ProducerEndpoint) UpdateExtensionBinaryFile(ctx context.Context, extensionId, binaryId int, zipPath string) error {
-	errorFormat := "UpdateExtensionBinaryFile: %v"
+	errorFormat := "UpdateExtensionBinaryFile: %w"
 
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
@@ -149,7 +164,7 @@ func (e ProducerEndpoint) UpdateExtensionBinaryFile(ctx context.Context, extensi
 }
 
 func (e ProducerEndpoint) UpdateExtensionIcon(ctx context.Context, extensionId int, iconFile string) error {
-	errorFormat := "UpdateExtensionIcon: %v"
+	errorFormat := "UpdateExtensionIcon: %w"
 
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
@@ -199,7 +214,7 @@ type ExtensionImage struct {
 }
 
 func (e ProducerEndpoint) GetExtensionImages(ctx context.Context, extensionId int) ([]*ExtensionImage, error) {
-	errorFormat := "GetExtensionImages: %v"
+	errorFormat := "GetExtensionImages: %w"
 
 	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins/%d/pictures", ApiUrl, extensionId), nil)
 	if err != nil {
@@ -220,7 +235,7 @@ func (e ProducerEndpoint) GetExtensionImages(ctx context.Context, extensionId in
 }
 
 func (e ProducerEndpoint) DeleteExtensionImages(ctx context.Context, extensionId int, imageId string) error {
-	errorFormat := "DeleteExtensionImages: %v"
+	errorFormat := "DeleteExtensionImages: %w"
 
 	r, err := e.c.NewAuthenticatedRequest(ctx, "DELETE", fmt.Sprintf("%s/plugins/%d/pictures/%s", ApiUrl, extensionId, imageId), nil)
 	if err != nil {
@@ -233,7 +248,7 @@ func (e ProducerEndpoint) DeleteExtensionImages(ctx context.Context, extensionId
 }
 
 func (e ProducerEndpoint) UpdateExtensionImage(ctx context.Context, extensionId int, image *ExtensionImage) error {
-	errorFormat := "UpdateExtensionImage: %v"
+	errorFormat := "UpdateExtensionImage: %w"
 
 	content, err := json.Marshal(image)
 	if err != nil {
@@ -251,7 +266,7 @@ func (e ProducerEndpoint) UpdateExtensionImage(ctx context.Context, extensionId
 }
 
 func (e ProducerEndpoint) AddExtensionImage(ctx context.Context, extensionId int, file string) (*ExtensionImage, error) {
-	errorFormat := "AddExtensionImage: %v"
+	errorFormat := "AddExtensionImage: %w"
 
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
@@ -289,14 +304,29 @@ func (e ProducerEndpoint) AddExtensionImage(ctx context.Context, extensionId int
 	var list []*ExtensionImage
 
 	if err = json.Unmarshal(body, &list); err != nil {
-		return nil, fmt.Errorf("AddExtensionImage: %v", err)
+		return nil, fmt.Errorf("AddExtensionImage: %w", err)
 	}
 
 	return list[0], nil
 }
 
+// DownloadStoreMedia fetches the content of a public store icon or image url.
+func (e ProducerEndpoint) DownloadStoreMedia(ctx context.Context, url string) ([]byte, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("DownloadStoreMedia: %w", err)
+	}
+
+	body, err := sendRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf("DownloadStoreMedia: %w", err)
+	}
+
+	return body, nil
+}
+
 func (e ProducerEndpoint) TriggerCodeReview(ctx context.Context, extensionId int) error {
-	errorFormat := "TriggerCodeReview: %v"
+	errorFormat := "TriggerCodeReview: %w"
 
 	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/plugins/%d/reviews", ApiUrl, extensionId), nil)
 	if err != nil {
@@ -309,7 +339,7 @@ func (e ProducerEndpoint) TriggerCodeReview(ctx context.Context, extensionId int
 }
 
 func (e ProducerEndpoint) GetBinaryReviewResults(ctx context.Context, extensionId, binaryId int) ([]BinaryReviewResult, error) {
-	errorFormat := "GetBinaryReviewResults: %v"
+	errorFormat := "GetBinaryReviewResults: %w"
 
 	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins/%d/binaries/%d/checkresults", ApiUrl, extensionId, binaryId), nil)
 	if err != nil {
@@ -329,6 +359,98 @@ func (e ProducerEndpoint) GetBinaryReviewResults(ctx context.Context, extensionI
 	return results, nil
 }
 
+// ExtensionBinaryUpload describes a new binary version of an extension.
+type ExtensionBinaryUpload struct {
+	Version          string
+	ZipPath          string
+	SoftwareVersions []string
+	Changelogs       []ExtensionUpdateChangelog
+}
+
+// UploadExtensionBinary creates the binary of the version when missing, updates its information and uploads the zip.
+func (e ProducerEndpoint) UploadExtensionBinary(ctx context.Context, extensionId int, upload ExtensionBinaryUpload) (*ExtensionBinary, bool, error) {
+	binaries, err := e.GetExtensionBinaries(ctx, extensionId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var binary *ExtensionBinary
+
+	for _, existing := range binaries {
+		if existing.Version == upload.Version {
+			binary = existing
+			break
+		}
+	}
+
+	created := false
+
+	if binary == nil {
+		binary, err = e.CreateExtensionBinary(ctx, extensionId, ExtensionCreate{
+			Version:          upload.Version,
+			SoftwareVersions: upload.SoftwareVersions,
+			Changelogs:       upload.Changelogs,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("create extension binary: %w", err)
+		}
+
+		created = true
+	}
+
+	err = e.UpdateExtensionBinaryInfo(ctx, extensionId, ExtensionUpdate{
+		Id:               binary.Id,
+		SoftwareVersions: upload.SoftwareVersions,
+		Changelogs:       upload.Changelogs,
+	})
+	if err != nil {
+		return nil, created, err
+	}
+
+	if err := e.UpdateExtensionBinaryFile(ctx, extensionId, binary.Id, upload.ZipPath); err != nil {
+		return nil, created, err
+	}
+
+	return binary, created, nil
+}
+
+// WaitForCodeReview polls the review results of the binary until a review after the given amount of known reviews has finished.
+// The poll interval starts at minInterval and doubles up to maxInterval, the waiting is limited by the deadline of the context.
+func (e ProducerEndpoint) WaitForCodeReview(ctx context.Context, extensionId, binaryId, knownReviews int, minInterval, maxInterval time.Duration) (*BinaryReviewResult, error) {
+	errorFormat := "WaitForCodeReview: %w"
+	interval := minInterval
+
+	for {
+		reviews, err := e.GetBinaryReviewResults(ctx, extensionId, binaryId)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf(errorFormat, ctx.Err())
+			}
+
+			return nil, err
+		}
+
+		if len(reviews) > knownReviews {
+			lastReview := reviews[len(reviews)-1]
+
+			if !lastReview.IsPending() {
+				return &lastReview, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf(errorFormat, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
 type BinaryReviewResult struct {
 	BinaryId int `json:"binaryId"`
 	Type     struct {
@@ -4,40 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-
-	"github.com/haokeyingxiao/haoke-cli/logging"
 )
 
 func (c *Client) GetMyProfile(ctx context.Context) (*MyProfile, error) {
-	errorFormat := "GetMyProfile: %v"
+	errorFormat := "GetMyProfile: %w"
 
 	request, err := c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/swplatform/account/%s", ApiUrl, c.Token.UserID), nil)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	resp, err := http.DefaultClient.Do(request)
+	data, err := c.doRequest(request)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			logging.FromContext(ctx).Errorf("GetMyProfile: %v", err)
-		}
-	}()
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf(errorFormat, err)
-	}
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf(errorFormat, err)
-	}
-
 	var profile MyProfile
 	if err := json.Unmarshal(data, &profile); err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
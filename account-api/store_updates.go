@@ -0,0 +1,62 @@
+package account_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// StoreUpdateRequest is an installed extension to look up updates for.
+type StoreUpdateRequest struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// StoreUpdate is the newest store version of an extension which supports the requested core version.
+type StoreUpdate struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// GetStoreUpdates returns the store updates of the extensions which are compatible with the given core version.
+// Extensions without such an update are missing in the result.
+func GetStoreUpdates(ctx context.Context, shopwareVersion string, extensions []StoreUpdateRequest) ([]StoreUpdate, error) {
+	return getStoreUpdates(ctx, ApiUrl, shopwareVersion, extensions)
+}
+
+func getStoreUpdates(ctx context.Context, baseUrl, shopwareVersion string, extensions []StoreUpdateRequest) ([]StoreUpdate, error) {
+	errorFormat := "GetStoreUpdates: %w"
+
+	s, err := json.Marshal(map[string][]StoreUpdateRequest{"plugins": extensions})
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	query := url.Values{"shopwareVersion": {shopwareVersion}, "language": {"en-GB"}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/swplatform/pluginupdates?%s", baseUrl, query.Encode()), bytes.NewBuffer(s))
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("user-agent", httpUserAgent)
+
+	data, err := sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	var response struct {
+		Data []StoreUpdate `json:"data"`
+	}
+
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	return response.Data, nil
+}
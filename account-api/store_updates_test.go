@@ -0,0 +1,34 @@
+package account_api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStoreUpdates(t *testing.T) {
+	useTestHTTPOptions(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/swplatform/pluginupdates", r.URL.Path)
+		assert.Equal(t, "6.6.0.0", r.URL.Query().Get("shopwareVersion"))
+
+		var body struct {
+			Plugins []StoreUpdateRequest `json:"plugins"`
+		}
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []StoreUpdateRequest{{Name: "FroshTools", Version: "1.0.0"}}, body.Plugins)
+
+		_, _ = w.Write([]byte(`{"data":[{"name":"FroshTools","version":"2.0.0","changelog":"Support 6.6"}]}`))
+	}))
+	defer server.Close()
+
+	updates, err := getStoreUpdates(context.Background(), server.URL, "6.6.0.0", []StoreUpdateRequest{{Name: "FroshTools", Version: "1.0.0"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []StoreUpdate{{Name: "FroshTools", Version: "2.0.0"}}, updates)
+}
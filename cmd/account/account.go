@@ -1,6 +1,8 @@
 package account
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 
 	account_api "github.com/haokeyingxiao/haoke-cli/account-api"
@@ -21,9 +23,42 @@ var services *ServiceContainer
 
 func Register(rootCmd *cobra.Command, onInit func(commandName string) (*ServiceContainer, error)) {
 	accountRootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		useAccountFlag(cmd)
+		useHTTPFlags(cmd)
 		ser, err := onInit(cmd.Name())
 		services = ser
 		return err
 	}
+	accountRootCmd.PersistentFlags().String("account", os.Getenv("HAOKE_CLI_ACCOUNT"), "Account profile to use")
+	accountRootCmd.PersistentFlags().Duration("api-timeout", account_api.DefaultHTTPOptions().Timeout, "Timeout of a single request to the account api, uploads only limit the wait for the response")
+	accountRootCmd.PersistentFlags().Int("api-retries", account_api.DefaultHTTPOptions().Retries, "Retries of rate limited or failed requests to the account api")
 	rootCmd.AddCommand(accountRootCmd)
 }
+
+// initConfigOnly is used by commands which only work with the config and need no login.
+func initConfigOnly(cmd *cobra.Command, _ []string) error {
+	useAccountFlag(cmd)
+	services = &ServiceContainer{Conf: config.Config{}}
+
+	return nil
+}
+
+func useAccountFlag(cmd *cobra.Command) {
+	if name, _ := cmd.Flags().GetString("account"); name != "" {
+		config.UseAccountProfile(name)
+	}
+}
+
+func useHTTPFlags(cmd *cobra.Command) {
+	options := account_api.DefaultHTTPOptions()
+
+	if timeout, err := cmd.Flags().GetDuration("api-timeout"); err == nil && timeout > 0 {
+		options.Timeout = timeout
+	}
+
+	if retries, err := cmd.Flags().GetInt("api-retries"); err == nil && retries >= 0 {
+		options.Retries = retries
+	}
+
+	account_api.SetHTTPOptions(options)
+}
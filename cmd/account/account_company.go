@@ -0,0 +1,86 @@
+package account
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	accountApi "github.com/haokeyingxiao/haoke-cli/account-api"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+var accountCompanyCmd = &cobra.Command{
+	Use:   "company",
+	Short: "Manage the companies you are member of",
+}
+
+var accountCompanyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all companies you are member of",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		memberships, err := services.AccountClient.GetMemberships(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"", "ID", "Name", "Customer number", "Roles"})
+
+		for _, membership := range memberships {
+			marker := ""
+			if membership.Company.Id == services.AccountClient.GetActiveCompanyID() {
+				marker = "*"
+			}
+
+			table.Append([]string{
+				marker,
+				membership.Company.Id,
+				membership.Company.Name,
+				membership.Company.CustomerNumber,
+				strings.Join(membership.GetRoles(), ", "),
+			})
+		}
+
+		table.Render()
+
+		return nil
+	},
+}
+
+var accountCompanyUseCmd = &cobra.Command{
+	Use:   "use [company]",
+	Short: "Uses the company (id, customer number or name) for the current account profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		memberships, err := services.AccountClient.GetMemberships(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		membership, err := accountApi.FindMembership(memberships, args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := services.Conf.SetAccountCompany(membership.Company.Id); err != nil {
+			return err
+		}
+
+		if err := services.Conf.Save(); err != nil {
+			return fmt.Errorf("cannot save config: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Using company %s for account profile %s", membership.Company.Name, services.Conf.GetAccountProfile())
+
+		return nil
+	},
+}
+
+func init() {
+	accountRootCmd.AddCommand(accountCompanyCmd)
+	accountCompanyCmd.AddCommand(accountCompanyListCmd)
+	accountCompanyCmd.AddCommand(accountCompanyUseCmd)
+}
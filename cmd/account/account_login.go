@@ -27,27 +27,35 @@ var loginCmd = &cobra.Command{
 			}
 
 			newCredentials = true
-
-			if err := services.Conf.SetAccountEmail(email); err != nil {
-				return err
-			}
-			if err := services.Conf.SetAccountPassword(password); err != nil {
-				return err
-			}
 		} else {
 			logging.FromContext(cmd.Context()).Infof("Using existing credentials. Use account:logout to logout")
 		}
 
-		client, err := accountApi.NewApi(cmd.Context(), accountApi.LoginRequest{Email: email, Password: password})
+		client, err := accountApi.NewApi(cmd.Context(), accountApi.LoginRequest{
+			Email:    email,
+			Password: password,
+			Profile:  services.Conf.GetAccountProfile(),
+			Company:  services.Conf.GetAccountCompany(),
+		})
 		if err != nil {
 			return fmt.Errorf("login failed with error: %w", err)
 		}
 
+		// only verified credentials are stored, so a typo does not end up in the keyring
 		if newCredentials {
+			if err := services.Conf.SetAccountEmail(email); err != nil {
+				return err
+			}
+			if err := services.Conf.SetAccountPassword(password); err != nil {
+				return err
+			}
+
 			err := services.Conf.Save()
 			if err != nil {
 				return fmt.Errorf("cannot save config: %w", err)
 			}
+		} else if err := services.Conf.MigrateAccountPassword(); err != nil {
+			return fmt.Errorf("cannot move the password into the secret store: %w", err)
 		}
 
 		profile, err := client.GetMyProfile(cmd.Context())
@@ -59,6 +67,12 @@ var loginCmd = &cobra.Command{
 			"Hey %s. You are now authenticated and can use all account commands",
 			profile.Name,
 		)
+
+		if services.Conf.GetAccountCompany() == "" {
+			if memberships, err := client.GetMemberships(cmd.Context()); err == nil && len(memberships) > 1 {
+				logging.FromContext(cmd.Context()).Infof("You are member of %d companies. Use account company use to select one", len(memberships))
+			}
+		}
 		return nil
 	},
 }
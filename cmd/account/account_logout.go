@@ -14,7 +14,7 @@ var logoutCmd = &cobra.Command{
 	Short: "Logout from Haoke Account",
 	Long:  ``,
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		err := accountApi.InvalidateTokenCache()
+		err := accountApi.InvalidateTokenCache(services.Conf.GetAccountProfile())
 		if err != nil {
 			return fmt.Errorf("cannot invalidate token cache: %w", err)
 		}
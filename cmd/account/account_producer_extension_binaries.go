@@ -0,0 +1,398 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	account_api "github.com/haokeyingxiao/haoke-cli/account-api"
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+	"github.com/haokeyingxiao/haoke-cli/version"
+)
+
+const codeReviewStatusPending = "pending"
+
+var accountCompanyProducerExtensionBinariesCmd = &cobra.Command{
+	Use:   "binaries",
+	Short: "Manage the uploaded versions of an extension",
+}
+
+var accountCompanyProducerExtensionBinariesListCmd = &cobra.Command{
+	Use:   "list [name]",
+	Short: "Lists all versions of the extension",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, storeExt, binaries, err := loadExtensionBinaries(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Version", "Status", "Compatible versions", "Review", "Last change"})
+
+		for _, binary := range binaries {
+			reviews, err := p.GetBinaryReviewResults(cmd.Context(), storeExt.Id, binary.Id)
+			if err != nil {
+				return err
+			}
+
+			table.Append([]string{
+				strconv.Itoa(binary.Id),
+				binary.Version,
+				binary.Status,
+				formatSoftwareVersionRange(binary.CompatibleSoftwareVersions),
+				binaryReviewStatus(reviews),
+				binary.LastChangeDate,
+			})
+		}
+
+		table.Render()
+
+		return nil
+	},
+}
+
+var accountCompanyProducerExtensionBinariesShowCmd = &cobra.Command{
+	Use:   "show [name] [version]",
+	Short: "Shows a version of the extension, defaults to the latest",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, storeExt, binaries, err := loadExtensionBinaries(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		binary, err := selectExtensionBinary(binaries, optionalArg(args, 1))
+		if err != nil {
+			return err
+		}
+
+		reviews, err := p.GetBinaryReviewResults(cmd.Context(), storeExt.Id, binary.Id)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]string, 0, len(binary.CompatibleSoftwareVersions))
+		for _, softwareVersion := range binary.CompatibleSoftwareVersions {
+			versions = append(versions, softwareVersion.Name)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetAutoWrapText(false)
+		table.Append([]string{"ID", strconv.Itoa(binary.Id)})
+		table.Append([]string{"Version", binary.Version})
+		table.Append([]string{"Status", binary.Status})
+		table.Append([]string{"Created", binary.CreationDate})
+		table.Append([]string{"Last change", binary.LastChangeDate})
+		table.Append([]string{"Review", binaryReviewStatus(reviews)})
+		table.Append([]string{"Compatible versions", strings.Join(versions, ", ")})
+
+		for _, changelog := range binary.Changelogs {
+			table.Append([]string{fmt.Sprintf("Changelog %s", changelog.Locale.Name), changelog.Text})
+		}
+
+		table.Render()
+
+		return nil
+	},
+}
+
+var accountCompanyProducerExtensionBinariesReviewResultsCmd = &cobra.Command{
+	Use:   "review-results [name] [version]",
+	Short: "Shows the code review results of a version, defaults to the latest",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputAsJson, _ := cmd.Flags().GetBool("json")
+
+		p, storeExt, binaries, err := loadExtensionBinaries(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		binary, err := selectExtensionBinary(binaries, optionalArg(args, 1))
+		if err != nil {
+			return err
+		}
+
+		reviews, err := p.GetBinaryReviewResults(cmd.Context(), storeExt.Id, binary.Id)
+		if err != nil {
+			return err
+		}
+
+		if outputAsJson {
+			content, err := json.Marshal(reviews)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+
+			return nil
+		}
+
+		if len(reviews) == 0 {
+			logging.FromContext(cmd.Context()).Infof("No code reviews found for version %s", binary.Version)
+
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"#", "Result", "Sub check", "Status", "Message"})
+		table.SetAutoWrapText(false)
+
+		for i, review := range reviews {
+			for _, result := range review.SubCheckResults {
+				table.Append([]string{strconv.Itoa(i + 1), review.Type.Name, result.SubCheck, result.Status, result.Message})
+			}
+		}
+
+		table.Render()
+
+		return nil
+	},
+}
+
+var accountCompanyProducerExtensionBinariesUpdateCompatibilityCmd = &cobra.Command{
+	Use:   "update-compatibility [name] [version]",
+	Short: "Recomputes the compatible software versions of a version, defaults to the latest",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		constraintFlag, _ := cmd.Flags().GetString("constraint")
+		extensionPath, _ := cmd.Flags().GetString("extension-path")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		constraint, err := binaryCompatibilityConstraint(constraintFlag, extensionPath)
+		if err != nil {
+			return err
+		}
+
+		p, storeExt, binaries, err := loadExtensionBinaries(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		binary, err := selectExtensionBinary(binaries, optionalArg(args, 1))
+		if err != nil {
+			return err
+		}
+
+		available, err := p.GetSoftwareVersions(cmd.Context(), storeExt.Type)
+		if err != nil {
+			return err
+		}
+
+		added, removed, versions := compatibilityChanges(binary.CompatibleSoftwareVersions, available.FilterOnVersion(constraint))
+
+		if len(added) == 0 && len(removed) == 0 {
+			logging.FromContext(cmd.Context()).Infof("Compatibility of version %s is up to date", binary.Version)
+
+			return nil
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Adding compatibility for: %s", strings.Join(added, ", "))
+		logging.FromContext(cmd.Context()).Infof("Removing compatibility for: %s", strings.Join(removed, ", "))
+
+		if dryRun {
+			return nil
+		}
+
+		changelogs := make([]account_api.ExtensionUpdateChangelog, 0, len(binary.Changelogs))
+		for _, changelog := range binary.Changelogs {
+			changelogs = append(changelogs, account_api.ExtensionUpdateChangelog{Locale: changelog.Locale.Name, Text: changelog.Text})
+		}
+
+		err = p.UpdateExtensionBinaryInfo(cmd.Context(), storeExt.Id, account_api.ExtensionUpdate{
+			Id:               binary.Id,
+			SoftwareVersions: versions,
+			Changelogs:       changelogs,
+		})
+		if err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Updated compatibility of version %s", binary.Version)
+
+		return nil
+	},
+}
+
+func init() {
+	accountCompanyProducerExtensionCmd.AddCommand(accountCompanyProducerExtensionBinariesCmd)
+	accountCompanyProducerExtensionBinariesCmd.AddCommand(accountCompanyProducerExtensionBinariesListCmd)
+	accountCompanyProducerExtensionBinariesCmd.AddCommand(accountCompanyProducerExtensionBinariesShowCmd)
+	accountCompanyProducerExtensionBinariesCmd.AddCommand(accountCompanyProducerExtensionBinariesReviewResultsCmd)
+	accountCompanyProducerExtensionBinariesCmd.AddCommand(accountCompanyProducerExtensionBinariesUpdateCompatibilityCmd)
+	accountCompanyProducerExtensionBinariesReviewResultsCmd.Flags().Bool("json", false, "Output as json")
+	accountCompanyProducerExtensionBinariesUpdateCompatibilityCmd.Flags().String("constraint", "", "Shopware version constraint of the version")
+	accountCompanyProducerExtensionBinariesUpdateCompatibilityCmd.Flags().String("extension-path", "", "Read the constraint from the extension folder or zip")
+	accountCompanyProducerExtensionBinariesUpdateCompatibilityCmd.Flags().Bool("dry-run", false, "Only show the changes")
+}
+
+func loadExtensionBinaries(ctx context.Context, name string) (*account_api.ProducerEndpoint, *account_api.Extension, []*account_api.ExtensionBinary, error) {
+	p, err := services.AccountClient.Producer(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot get producer endpoint: %w", err)
+	}
+
+	storeExt, err := p.GetExtensionByName(ctx, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	binaries, err := p.GetExtensionBinaries(ctx, storeExt.Id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return p, storeExt, binaries, nil
+}
+
+func optionalArg(args []string, index int) string {
+	if len(args) > index {
+		return args[index]
+	}
+
+	return ""
+}
+
+// selectExtensionBinary returns the binary with the given version or the highest version when empty.
+func selectExtensionBinary(binaries []*account_api.ExtensionBinary, binaryVersion string) (*account_api.ExtensionBinary, error) {
+	var latest *account_api.ExtensionBinary
+	var latestVersion *version.Version
+
+	for _, binary := range binaries {
+		if binaryVersion != "" {
+			if binary.Version == binaryVersion {
+				return binary, nil
+			}
+
+			continue
+		}
+
+		v, err := version.NewVersion(binary.Version)
+		if err != nil {
+			continue
+		}
+
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latest = binary
+			latestVersion = v
+		}
+	}
+
+	if binaryVersion != "" {
+		return nil, fmt.Errorf("version %s not found", binaryVersion)
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("extension has no versions")
+	}
+
+	return latest, nil
+}
+
+func binaryCompatibilityConstraint(constraint, extensionPath string) (*version.Constraints, error) {
+	if constraint != "" && extensionPath != "" {
+		return nil, fmt.Errorf("constraint and extension-path cannot be used together")
+	}
+
+	if constraint != "" {
+		c, err := version.NewConstraint(constraint)
+		if err != nil {
+			return nil, err
+		}
+
+		return &c, nil
+	}
+
+	if extensionPath == "" {
+		return nil, fmt.Errorf("either constraint or extension-path is required")
+	}
+
+	stat, err := os.Stat(extensionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var ext extension.Extension
+
+	if stat.IsDir() {
+		ext, err = extension.GetExtensionByFolder(extensionPath)
+	} else {
+		ext, err = extension.GetExtensionByZip(extensionPath)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot open extension: %w", err)
+	}
+
+	return ext.GetShopwareVersionConstraint()
+}
+
+// compatibilityChanges compares the current compatible versions with the expected ones and returns the new list.
+func compatibilityChanges(current, expected account_api.SoftwareVersionList) ([]string, []string, []string) {
+	currentNames := make([]string, 0, len(current))
+	for _, softwareVersion := range current {
+		currentNames = append(currentNames, softwareVersion.Name)
+	}
+
+	expectedNames := make([]string, 0, len(expected))
+	for _, softwareVersion := range expected {
+		expectedNames = append(expectedNames, softwareVersion.Name)
+	}
+
+	added := make([]string, 0)
+	for _, name := range expectedNames {
+		if !slices.Contains(currentNames, name) {
+			added = append(added, name)
+		}
+	}
+
+	removed := make([]string, 0)
+	for _, name := range currentNames {
+		if !slices.Contains(expectedNames, name) {
+			removed = append(removed, name)
+		}
+	}
+
+	return added, removed, expectedNames
+}
+
+func formatSoftwareVersionRange(versions account_api.SoftwareVersionList) string {
+	if len(versions) == 0 {
+		return "-"
+	}
+
+	if len(versions) == 1 {
+		return versions[0].Name
+	}
+
+	return fmt.Sprintf("%s - %s", versions[0].Name, versions[len(versions)-1].Name)
+}
+
+func binaryReviewStatus(reviews []account_api.BinaryReviewResult) string {
+	if len(reviews) == 0 {
+		return "-"
+	}
+
+	review := reviews[len(reviews)-1]
+
+	switch {
+	case review.IsPending():
+		return codeReviewStatusPending
+	case !review.HasPassed():
+		return account_api.CodeReviewStatusFailed
+	case review.HasWarnings():
+		return account_api.CodeReviewStatusPassedWithWarnings
+	}
+
+	return account_api.CodeReviewStatusPassed
+}
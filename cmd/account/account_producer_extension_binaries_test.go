@@ -0,0 +1,66 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	account_api "github.com/haokeyingxiao/haoke-cli/account-api"
+	"github.com/haokeyingxiao/haoke-cli/version"
+)
+
+func TestSelectExtensionBinary(t *testing.T) {
+	binaries := []*account_api.ExtensionBinary{
+		{Id: 1, Version: "1.0.0"},
+		{Id: 3, Version: "1.10.0"},
+		{Id: 2, Version: "1.2.0"},
+	}
+
+	latest, err := selectExtensionBinary(binaries, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, latest.Id)
+
+	specific, err := selectExtensionBinary(binaries, "1.2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, specific.Id)
+
+	_, err = selectExtensionBinary(binaries, "2.0.0")
+	assert.Error(t, err)
+
+	_, err = selectExtensionBinary(nil, "")
+	assert.Error(t, err)
+}
+
+func TestCompatibilityChanges(t *testing.T) {
+	available := account_api.SoftwareVersionList{
+		{Name: "6.5.0.0", Selectable: true},
+		{Name: "6.5.1.0", Selectable: true},
+		{Name: "6.6.0.0", Selectable: true},
+		{Name: "6.6.1.0", Selectable: false},
+	}
+
+	constraint, err := version.NewConstraint("~6.5.0 || ~6.6.0")
+	assert.NoError(t, err)
+
+	current := account_api.SoftwareVersionList{{Name: "6.4.0.0"}, {Name: "6.5.0.0"}}
+
+	added, removed, versions := compatibilityChanges(current, available.FilterOnVersion(&constraint))
+
+	assert.Equal(t, []string{"6.5.1.0", "6.6.0.0"}, added)
+	assert.Equal(t, []string{"6.4.0.0"}, removed)
+	assert.Equal(t, []string{"6.5.0.0", "6.5.1.0", "6.6.0.0"}, versions)
+}
+
+func TestBinaryReviewStatus(t *testing.T) {
+	assert.Equal(t, "-", binaryReviewStatus(nil))
+
+	passed := account_api.BinaryReviewResult{}
+	passed.Type.Id = 3
+
+	pending := account_api.BinaryReviewResult{}
+	pending.Type.Id = 4
+
+	assert.Equal(t, account_api.CodeReviewStatusPassed, binaryReviewStatus([]account_api.BinaryReviewResult{pending, passed}))
+	assert.Equal(t, codeReviewStatusPending, binaryReviewStatus([]account_api.BinaryReviewResult{passed, pending}))
+	assert.Equal(t, account_api.CodeReviewStatusFailed, binaryReviewStatus([]account_api.BinaryReviewResult{{}}))
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -50,16 +51,6 @@ var accountCompanyProducerExtensionInfoPullCmd = &cobra.Command{
 
 		resourcesFolder := path.Join(zipExt.GetPath(), "src/Resources/store/")
 		categoryList := make([]string, 0)
-		tagsDE := make([]string, 0)
-		tagsEN := make([]string, 0)
-		videosDE := make([]string, 0)
-		videosEN := make([]string, 0)
-		highlightsDE := make([]string, 0)
-		highlightsEN := make([]string, 0)
-		featuresDE := make([]string, 0)
-		featuresEN := make([]string, 0)
-		faqDE := make([]extension.ConfigStoreFaq, 0)
-		faqEN := make([]extension.ConfigStoreFaq, 0)
 
 		if _, err := os.Stat(resourcesFolder); os.IsNotExist(err) {
 			err = os.MkdirAll(resourcesFolder, os.ModePerm)
@@ -96,84 +87,23 @@ var accountCompanyProducerExtensionInfoPullCmd = &cobra.Command{
 		if len(storeImages) > 0 {
 			imagesDir := path.Join(zipExt.GetPath(), "src/Resources/store/images/")
 
-			if err := writeImages(cmd.Context(), imagesDir, 0, storeImages); err != nil {
-				return fmt.Errorf("cannot write images: %w", err)
-			}
-
-			if err := writeImages(cmd.Context(), imagesDir, 1, storeImages); err != nil {
+			if err := writeImages(cmd.Context(), imagesDir, storeImages); err != nil {
 				return fmt.Errorf("cannot write images: %w", err)
 			}
 		}
 
-		germanDescription := ""
-		englishDescription := ""
-		germanInstallationManual := ""
-		englishInstallationManual := ""
+		store := extension.ConfigStore{}
 
 		for _, info := range storeExt.Infos {
-			language := info.Locale.Name[0:2]
-
-			if language == "de" {
-				germanDescription = "file:src/Resources/store/description.de.html"
-				germanInstallationManual = "file:src/Resources/store/installation_manual.de.html"
-
-				if err := os.WriteFile(path.Join(zipExt.GetPath(), germanDescription[5:]), []byte(info.Description), os.ModePerm); err != nil {
-					return fmt.Errorf("cannot write file: %w", err)
-				}
-
-				if err := os.WriteFile(path.Join(zipExt.GetPath(), germanInstallationManual[5:]), []byte(info.InstallationManual), os.ModePerm); err != nil {
-					return fmt.Errorf("cannot write file: %w", err)
-				}
+			language := extension.LanguageFromLocale(info.Locale.Name)
 
-				for _, element := range info.Tags {
-					tagsDE = append(tagsDE, element.Name)
-				}
-
-				for _, element := range info.Videos {
-					videosDE = append(videosDE, element.URL)
-				}
-
-				if info.Highlights != "" {
-					highlightsDE = append(highlightsDE, strings.Split(info.Highlights, "\n")...)
-				}
-				if info.Features != "" {
-					featuresDE = append(featuresDE, strings.Split(info.Features, "\n")...)
-				}
-
-				for _, element := range info.Faqs {
-					faqDE = append(faqDE, extension.ConfigStoreFaq{Question: element.Question, Answer: element.Answer})
-				}
-			} else {
-				englishDescription = "file:src/Resources/store/description.en.html"
-				englishInstallationManual = "file:src/Resources/store/installation_manual.en.html"
-
-				if err := os.WriteFile(path.Join(zipExt.GetPath(), englishDescription[5:]), []byte(info.Description), os.ModePerm); err != nil {
-					return fmt.Errorf("cannot write file: %w", err)
-				}
-
-				if err := os.WriteFile(path.Join(zipExt.GetPath(), englishInstallationManual[5:]), []byte(info.InstallationManual), os.ModePerm); err != nil {
-					return fmt.Errorf("cannot write file: %w", err)
-				}
-
-				for _, element := range info.Tags {
-					tagsEN = append(tagsEN, element.Name)
-				}
-
-				for _, element := range info.Videos {
-					videosEN = append(videosEN, element.URL)
-				}
-
-				if info.Highlights != "" {
-					highlightsEN = append(highlightsEN, strings.Split(info.Highlights, "\n")...)
-				}
-
-				if info.Features != "" {
-					featuresEN = append(featuresEN, strings.Split(info.Features, "\n")...)
-				}
+			if !slices.Contains(extension.ConfigLanguages, language) {
+				logging.FromContext(cmd.Context()).Warnf("Skipping store information of unsupported locale %s", info.Locale.Name)
+				continue
+			}
 
-				for _, element := range info.Faqs {
-					faqEN = append(faqEN, extension.ConfigStoreFaq{Question: element.Question, Answer: element.Answer})
-				}
+			if err := pullStoreInfo(zipExt.GetPath(), language, info, &store); err != nil {
+				return err
 			}
 		}
 
@@ -189,14 +119,14 @@ var accountCompanyProducerExtensionInfoPullCmd = &cobra.Command{
 		newCfg.Store.DefaultLocale = &storeExt.StandardLocale.Name
 		newCfg.Store.Type = &extType
 		newCfg.Store.AutomaticBugfixVersionCompatibility = &storeExt.AutomaticBugfixVersionCompatibility
-		newCfg.Store.Description = extension.ConfigTranslated[string]{German: &germanDescription, English: &englishDescription}
-		newCfg.Store.InstallationManual = extension.ConfigTranslated[string]{German: &germanInstallationManual, English: &englishInstallationManual}
+		newCfg.Store.Description = store.Description
+		newCfg.Store.InstallationManual = store.InstallationManual
 		newCfg.Store.Categories = &categoryList
-		newCfg.Store.Tags = extension.ConfigTranslated[[]string]{German: &tagsDE, English: &tagsEN}
-		newCfg.Store.Videos = extension.ConfigTranslated[[]string]{German: &videosDE, English: &videosEN}
-		newCfg.Store.Highlights = extension.ConfigTranslated[[]string]{German: &highlightsDE, English: &highlightsEN}
-		newCfg.Store.Features = extension.ConfigTranslated[[]string]{German: &featuresDE, English: &featuresEN}
-		newCfg.Store.Faq = extension.ConfigTranslated[[]extension.ConfigStoreFaq]{German: &faqDE, English: &faqEN}
+		newCfg.Store.Tags = store.Tags
+		newCfg.Store.Videos = store.Videos
+		newCfg.Store.Highlights = store.Highlights
+		newCfg.Store.Features = store.Features
+		newCfg.Store.Faq = store.Faq
 		newCfg.Store.Images = nil
 
 		if len(storeImages) > 0 {
@@ -256,42 +186,101 @@ func downloadFileTo(ctx context.Context, url string, target string) error {
 	return nil
 }
 
-func writeImages(ctx context.Context, imagePath string, index int, storeImages []*account_api.ExtensionImage) error {
-	imageMap := make(map[int]string)
+// pullStoreInfo writes the description files of one language and copies the translated store fields into the config.
+func pullStoreInfo(extensionPath, language string, info *account_api.ExtensionInfo, store *extension.ConfigStore) error {
+	description := fmt.Sprintf("file:src/Resources/store/description.%s.html", language)
+	installationManual := fmt.Sprintf("file:src/Resources/store/installation_manual.%s.html", language)
+
+	if err := os.WriteFile(path.Join(extensionPath, strings.TrimPrefix(description, "file:")), []byte(info.Description), os.ModePerm); err != nil {
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+
+	if err := os.WriteFile(path.Join(extensionPath, strings.TrimPrefix(installationManual, "file:")), []byte(info.InstallationManual), os.ModePerm); err != nil {
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+
+	tags := make([]string, 0)
+	for _, element := range info.Tags {
+		tags = append(tags, element.Name)
+	}
+
+	videos := make([]string, 0)
+	for _, element := range info.Videos {
+		videos = append(videos, element.URL)
+	}
+
+	highlights := make([]string, 0)
+	if info.Highlights != "" {
+		highlights = append(highlights, strings.Split(info.Highlights, "\n")...)
+	}
+
+	features := make([]string, 0)
+	if info.Features != "" {
+		features = append(features, strings.Split(info.Features, "\n")...)
+	}
+
+	faq := make([]extension.ConfigStoreFaq, 0)
+	for _, element := range info.Faqs {
+		faq = append(faq, extension.ConfigStoreFaq{Question: element.Question, Answer: element.Answer})
+	}
+
+	store.Description.Set(language, &description)
+	store.InstallationManual.Set(language, &installationManual)
+	store.Tags.Set(language, &tags)
+	store.Videos.Set(language, &videos)
+	store.Highlights.Set(language, &highlights)
+	store.Features.Set(language, &features)
+	store.Faq.Set(language, &faq)
+
+	return nil
+}
+
+func writeImages(ctx context.Context, imagePath string, storeImages []*account_api.ExtensionImage) error {
+	imageMaps := make(map[string]map[int]string)
 
 	for _, image := range storeImages {
-		if image.Details[index].Activated {
+		for _, detail := range image.Details {
+			if !detail.Activated {
+				continue
+			}
+
+			language := extension.LanguageFromLocale(detail.Locale.Name)
+
+			if !slices.Contains(extension.ConfigLanguages, language) {
+				continue
+			}
+
+			if _, ok := imageMaps[language]; !ok {
+				imageMaps[language] = make(map[int]string)
+			}
+
+			imageMap := imageMaps[language]
 			priority := image.Priority
 
-			if _, ok := imageMap[priority]; !ok {
-				imageMap[priority] = image.RemoteLink
-			} else {
-				for {
-					priority++
-					if _, ok := imageMap[priority]; !ok {
-						imageMap[priority] = image.RemoteLink
-						break
-					}
+			for {
+				if _, ok := imageMap[priority]; !ok {
+					imageMap[priority] = image.RemoteLink
+					break
 				}
+
+				priority++
 			}
 		}
 	}
 
-	if index == 0 {
-		imagePath = path.Join(imagePath, "de")
-	} else {
-		imagePath = path.Join(imagePath, "en")
-	}
+	for language, imageMap := range imageMaps {
+		languagePath := path.Join(imagePath, language)
 
-	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
-		if err := os.MkdirAll(imagePath, os.ModePerm); err != nil {
-			return err
+		if _, err := os.Stat(languagePath); os.IsNotExist(err) {
+			if err := os.MkdirAll(languagePath, os.ModePerm); err != nil {
+				return err
+			}
 		}
-	}
 
-	for priority, link := range imageMap {
-		if err := downloadFileTo(ctx, link, path.Join(imagePath, fmt.Sprintf("%d.png", priority))); err != nil {
-			return err
+		for priority, link := range imageMap {
+			if err := downloadFileTo(ctx, link, path.Join(languagePath, fmt.Sprintf("%d.png", priority))); err != nil {
+				return err
+			}
 		}
 	}
 
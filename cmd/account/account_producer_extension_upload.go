@@ -42,138 +42,64 @@ var accountCompanyProducerExtensionUploadCmd = &cobra.Command{
 			return err
 		}
 
-		binaries, err := p.GetExtensionBinaries(cmd.Context(), ext.Id)
-		if err != nil {
-			return err
-		}
-
 		zipVersion, err := zipExt.GetVersion()
 		if err != nil {
 			return err
 		}
 
-		var foundBinary *account_api.ExtensionBinary
-
-		for _, binary := range binaries {
-			if binary.Version == zipVersion.String() {
-				foundBinary = binary
-				break
-			}
-		}
-
 		changelog, err := zipExt.GetChangelog()
 		if err != nil {
 			return err
 		}
 
-		avaiableVersions, err := p.GetSoftwareVersions(cmd.Context(), zipExt.GetType())
+		softwareVersions, err := extension.CompatibleSoftwareVersions(cmd.Context(), p, zipExt)
 		if err != nil {
 			return err
 		}
 
-		constraint, err := zipExt.GetShopwareVersionConstraint()
+		binary, created, err := p.UploadExtensionBinary(cmd.Context(), ext.Id, account_api.ExtensionBinaryUpload{
+			Version:          zipVersion.String(),
+			ZipPath:          path,
+			SoftwareVersions: softwareVersions,
+			Changelogs:       account_api.BinaryChangelogs(changelog.German, changelog.English, changelog.Chinese),
+		})
 		if err != nil {
 			return err
 		}
 
-		if foundBinary == nil {
-			create := account_api.ExtensionCreate{
-				Version:          zipVersion.String(),
-				SoftwareVersions: avaiableVersions.FilterOnVersionStringList(constraint),
-				Changelogs: []account_api.ExtensionUpdateChangelog{
-					{Locale: "de_DE", Text: changelog.German},
-					{Locale: "en_GB", Text: changelog.English},
-					{Locale: "zh_CN", Text: changelog.Chinese},
-				},
-			}
-
-			foundBinary, err = p.CreateExtensionBinary(cmd.Context(), ext.Id, create)
-			if err != nil {
-				return fmt.Errorf("create extension binary: %w", err)
-			}
-
-			logging.FromContext(cmd.Context()).Infof("Created new binary with version %s", zipVersion)
+		if created {
+			logging.FromContext(cmd.Context()).Infof("Created new binary with version %s and uploaded the zip", zipVersion)
 		} else {
-			logging.FromContext(cmd.Context()).Infof("Found a zip with version %s already. Updating it", zipVersion)
+			logging.FromContext(cmd.Context()).Infof("Found a zip with version %s already. Updated it", zipVersion)
 		}
 
-		update := account_api.ExtensionUpdate{
-			Id:               foundBinary.Id,
-			SoftwareVersions: avaiableVersions.FilterOnVersionStringList(constraint),
-			Changelogs: []account_api.ExtensionUpdateChangelog{
-				{Locale: "de_DE", Text: changelog.German},
-				{Locale: "en_GB", Text: changelog.English},
-				{Locale: "zh_CN", Text: changelog.Chinese},
-			},
-		}
+		logging.FromContext(cmd.Context()).Infof("Submitting code review request")
 
-		err = p.UpdateExtensionBinaryInfo(cmd.Context(), ext.Id, update)
+		beforeReviews, err := p.GetBinaryReviewResults(cmd.Context(), ext.Id, binary.Id)
 		if err != nil {
 			return err
 		}
 
-		logging.FromContext(cmd.Context()).Infof("Updated changelog. Uploading now the zip to remote")
-
-		err = p.UpdateExtensionBinaryFile(cmd.Context(), ext.Id, foundBinary.Id, path)
+		err = p.TriggerCodeReview(cmd.Context(), ext.Id)
 		if err != nil {
 			return err
 		}
 
-		logging.FromContext(cmd.Context()).Infof("Submitting code review request")
-
-		beforeReviews, err := p.GetBinaryReviewResults(cmd.Context(), ext.Id, foundBinary.Id)
-		if err != nil {
-			return err
+		if skipWaitingForCodereviewResult {
+			return nil
 		}
 
-		err = p.TriggerCodeReview(cmd.Context(), ext.Id)
+		reviewTimeout, _ := cmd.Flags().GetDuration("review-timeout")
+		reviewOutput, _ := cmd.Flags().GetString("review-output")
+
+		logging.FromContext(cmd.Context()).Infof("Waiting up to %s for the code review result", reviewTimeout)
+
+		report, err := p.WaitForCodeReviewReport(cmd.Context(), ext.Id, binary.Id, len(beforeReviews), reviewTimeout)
 		if err != nil {
 			return err
 		}
 
-		if !skipWaitingForCodereviewResult {
-			logging.FromContext(cmd.Context()).Infof("Waiting for code review result")
-
-			time.Sleep(10 * time.Second)
-
-			maxTries := 10
-			tried := 0
-			for {
-				reviews, err := p.GetBinaryReviewResults(cmd.Context(), ext.Id, foundBinary.Id)
-				if err != nil {
-					return err
-				}
-
-				// Review has been updated
-				if len(reviews) != len(beforeReviews) {
-					lastReview := reviews[len(reviews)-1]
-
-					if !lastReview.IsPending() {
-						if lastReview.HasPassed() {
-							if lastReview.HasWarnings() {
-								logging.FromContext(cmd.Context()).Infof("Code review has been passed but with warnings")
-								logging.FromContext(cmd.Context()).Infof(lastReview.GetSummary())
-							} else {
-								logging.FromContext(cmd.Context()).Infof("Code review has been passed without warnings")
-							}
-
-							break
-						}
-
-						logging.FromContext(cmd.Context()).Fatalln("Code review has not passed", lastReview.GetSummary())
-					}
-				}
-
-				time.Sleep(15 * time.Second)
-				tried++
-
-				if maxTries == tried {
-					logging.FromContext(cmd.Context()).Infof("Skipping waiting for code review result as it took too long")
-				}
-			}
-		}
-
-		return nil
+		return account_api.FinishCodeReview(cmd.Context(), report, reviewOutput)
 	},
 }
 
@@ -181,5 +107,7 @@ var skipWaitingForCodereviewResult bool
 
 func init() {
 	accountCompanyProducerExtensionCmd.AddCommand(accountCompanyProducerExtensionUploadCmd)
-	accountCompanyProducerExtensionCmd.Flags().BoolVar(&skipWaitingForCodereviewResult, "skip-for-review-result", true, "Skips waiting for Code review result")
+	accountCompanyProducerExtensionUploadCmd.Flags().BoolVar(&skipWaitingForCodereviewResult, "skip-for-review-result", true, "Skips waiting for Code review result")
+	accountCompanyProducerExtensionUploadCmd.Flags().Duration("review-timeout", 10*time.Minute, "Maximum time to wait for the code review result")
+	accountCompanyProducerExtensionUploadCmd.Flags().String("review-output", "", "Write the code review result as JSON to this file, - for stdout")
 }
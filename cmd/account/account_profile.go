@@ -0,0 +1,88 @@
+package account
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+var accountListCmd = &cobra.Command{
+	Use:               "list",
+	Short:             "Lists all account profiles",
+	PersistentPreRunE: initConfigOnly,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		current := services.Conf.GetAccountProfile()
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"", "Profile", "Email", "Company"})
+
+		for _, name := range services.Conf.GetAccountProfiles() {
+			marker := ""
+			if name == current {
+				marker = "*"
+			}
+
+			email, company := services.Conf.GetAccountProfileDetails(name)
+
+			table.Append([]string{marker, name, email, company})
+		}
+
+		table.Render()
+
+		return nil
+	},
+}
+
+var accountSwitchCmd = &cobra.Command{
+	Use:               "switch [profile]",
+	Short:             "Changes the account profile used by default",
+	Args:              cobra.MaximumNArgs(1),
+	PersistentPreRunE: initConfigOnly,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var name string
+
+		if len(args) > 0 {
+			name = args[0]
+		} else {
+			profiles := services.Conf.GetAccountProfiles()
+
+			if len(profiles) == 0 {
+				return fmt.Errorf("no account profiles configured, use account login --account <profile> to create one")
+			}
+
+			prompt := promptui.Select{
+				Label: "Select account profile",
+				Items: profiles,
+			}
+
+			_, selected, err := prompt.Run()
+			if err != nil {
+				return err
+			}
+
+			name = selected
+		}
+
+		if err := services.Conf.SetCurrentAccountProfile(name); err != nil {
+			return err
+		}
+
+		if err := services.Conf.Save(); err != nil {
+			return fmt.Errorf("cannot save config: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Switched to account profile %s", name)
+
+		return nil
+	},
+}
+
+func init() {
+	accountRootCmd.AddCommand(accountListCmd)
+	accountRootCmd.AddCommand(accountSwitchCmd)
+}
@@ -0,0 +1,105 @@
+package extension
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/internal/git"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+var extensionBumpCmd = &cobra.Command{
+	Use:       "bump [major|minor|patch|auto] [path]",
+	Short:     "Increases the version of the extension and adds a changelog section",
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: extension.VersionBumps,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skipChangelog, _ := cmd.Flags().GetBool("skip-changelog")
+		createTag, _ := cmd.Flags().GetBool("tag")
+		bump := args[0]
+
+		extPath, err := filepath.Abs(args[1])
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("cannot open extension: %w", err)
+		}
+
+		current, err := ext.GetVersion()
+		if err != nil {
+			return err
+		}
+
+		commits, err := git.GetCommits(cmd.Context(), extPath)
+		if err != nil {
+			if bump == extension.VersionBumpAuto {
+				return fmt.Errorf("cannot detect version bump: %w", err)
+			}
+
+			logging.FromContext(cmd.Context()).Warnf("Cannot read commits: %v", err)
+		}
+
+		if bump == extension.VersionBumpAuto {
+			bump = extension.DetectVersionBump(commits)
+
+			logging.FromContext(cmd.Context()).Infof("Detected a %s release from %d commits", bump, len(commits))
+		}
+
+		next, err := extension.BumpVersion(current, bump)
+		if err != nil {
+			return err
+		}
+
+		entries := extension.ChangelogEntriesOfCommits(commits)
+
+		// fail before anything is written, an empty section would end up in the release
+		if !skipChangelog && len(entries) == 0 {
+			return fmt.Errorf("no changelog entries found in the commits, update the changelog manually and use --skip-changelog")
+		}
+
+		if err := extension.WriteExtensionVersion(ext, next); err != nil {
+			return fmt.Errorf("cannot write version: %w", err)
+		}
+
+		changedFiles := []string{extension.VersionFile(ext)}
+
+		if !skipChangelog {
+			files, err := extension.PrependChangelogVersion(ext, next, entries)
+			if err != nil {
+				return fmt.Errorf("cannot update changelog: %w", err)
+			}
+
+			changedFiles = append(changedFiles, files...)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Bumped version from %s to %s", current.String(), next.String())
+
+		if createTag {
+			if err := git.CommitFiles(cmd.Context(), extPath, fmt.Sprintf("chore: release %s", next.String()), changedFiles...); err != nil {
+				return err
+			}
+
+			if err := git.CreateTag(cmd.Context(), extPath, next.String(), fmt.Sprintf("Release %s", next.String())); err != nil {
+				return err
+			}
+
+			logging.FromContext(cmd.Context()).Infof("Created tag %s", next.String())
+		}
+
+		fmt.Println(next.String())
+
+		return nil
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionBumpCmd)
+	extensionBumpCmd.Flags().Bool("skip-changelog", false, "Do not add a section to the changelog files")
+	extensionBumpCmd.Flags().Bool("tag", false, "Commit the changed files and create a git tag of the new version")
+}
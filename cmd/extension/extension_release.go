@@ -0,0 +1,222 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	accountApi "github.com/haokeyingxiao/haoke-cli/account-api"
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/internal/config"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+const (
+	releaseStepDone    = "done"
+	releaseStepSkipped = "skipped"
+	releaseStepFailed  = "failed"
+)
+
+type releaseStep struct {
+	Name    string
+	Status  string
+	Details string
+}
+
+type releaseSummary struct {
+	Steps []releaseStep
+}
+
+func (s *releaseSummary) add(name, status, details string) {
+	s.Steps = append(s.Steps, releaseStep{Name: name, Status: status, Details: details})
+}
+
+// fail records the failed step and returns the error for the caller.
+func (s *releaseSummary) fail(name string, err error) error {
+	s.add(name, releaseStepFailed, err.Error())
+
+	return err
+}
+
+func (s *releaseSummary) render() {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Step", "Status", "Details"})
+	table.SetAutoWrapText(false)
+
+	for _, step := range s.Steps {
+		table.Append([]string{step.Name, step.Status, step.Details})
+	}
+
+	table.Render()
+}
+
+var extensionReleaseCmd = &cobra.Command{
+	Use:   "release [path]",
+	Short: "Validates, zips and uploads a new version of the extension to the store",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		skipStoreInfo, _ := cmd.Flags().GetBool("skip-store-info")
+		skipReview, _ := cmd.Flags().GetBool("skip-review")
+		reviewTimeout, _ := cmd.Flags().GetDuration("review-timeout")
+		reviewOutput, _ := cmd.Flags().GetString("review-output")
+		disableGit, _ := cmd.Flags().GetBool("disable-git")
+		gitCommit, _ := cmd.Flags().GetString("git-commit")
+		outputDir, _ := cmd.Flags().GetString("output-directory")
+
+		if accountProfile, _ := cmd.Flags().GetString("account"); accountProfile != "" {
+			config.UseAccountProfile(accountProfile)
+		}
+
+		extPath, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		summary := &releaseSummary{}
+		defer summary.render()
+
+		if err := extension.RunBeforeValidateHooks(cmd.Context(), ext, true); err != nil {
+			return summary.fail("validate", fmt.Errorf("before hooks validate: %w", err))
+		}
+
+		validation := extension.RunValidation(cmd.Context(), ext)
+		printValidationResult(validation)
+
+		if validation.HasErrors() {
+			return summary.fail("validate", fmt.Errorf("validation failed with %d errors", len(validation.Errors())))
+		}
+
+		summary.add("validate", releaseStepDone, fmt.Sprintf("%d warnings", len(validation.Warnings())))
+
+		extVersion, err := ext.GetVersion()
+		if err != nil {
+			return summary.fail("changelog", err)
+		}
+
+		changelog, err := ext.GetChangelog()
+		if err != nil {
+			return summary.fail("changelog", fmt.Errorf("changelog does not contain version %s: %w", extVersion.String(), err))
+		}
+
+		summary.add("changelog", releaseStepDone, fmt.Sprintf("contains version %s", extVersion.String()))
+
+		zipFile, err := zipExtension(cmd.Context(), ext, extensionZipOptions{
+			DisableGit: disableGit,
+			GitCommit:  gitCommit,
+			Release:    true,
+			OutputDir:  outputDir,
+		})
+		if err != nil {
+			return summary.fail("zip", err)
+		}
+
+		summary.add("zip", releaseStepDone, zipFile)
+
+		client, err := accountApi.NewApi(cmd.Context(), config.Config{})
+		if err != nil {
+			return summary.fail("upload", err)
+		}
+
+		p, err := client.Producer(cmd.Context())
+		if err != nil {
+			return summary.fail("upload", err)
+		}
+
+		name, err := ext.GetName()
+		if err != nil {
+			return summary.fail("upload", err)
+		}
+
+		storeExt, err := p.GetExtensionByName(cmd.Context(), name)
+		if err != nil {
+			return summary.fail("upload", err)
+		}
+
+		softwareVersions, err := extension.CompatibleSoftwareVersions(cmd.Context(), p, ext)
+		if err != nil {
+			return summary.fail("upload", err)
+		}
+
+		binary, created, err := p.UploadExtensionBinary(cmd.Context(), storeExt.Id, accountApi.ExtensionBinaryUpload{
+			Version:          extVersion.String(),
+			ZipPath:          zipFile,
+			SoftwareVersions: softwareVersions,
+			Changelogs:       accountApi.BinaryChangelogs(changelog.German, changelog.English, changelog.Chinese),
+		})
+		if err != nil {
+			return summary.fail("upload", err)
+		}
+
+		if created {
+			summary.add("upload", releaseStepDone, fmt.Sprintf("created binary %s", extVersion.String()))
+		} else {
+			summary.add("upload", releaseStepDone, fmt.Sprintf("updated existing binary %s", extVersion.String()))
+		}
+
+		if skipStoreInfo {
+			summary.add("store info", releaseStepSkipped, "")
+		} else {
+			changed, err := extension.PushStoreInfo(cmd.Context(), p, ext, true)
+			if err != nil {
+				return summary.fail("store info", err)
+			}
+
+			if changed {
+				summary.add("store info", releaseStepDone, "updated")
+			} else {
+				summary.add("store info", releaseStepSkipped, "unchanged")
+			}
+		}
+
+		if skipReview {
+			summary.add("code review", releaseStepSkipped, "")
+
+			return nil
+		}
+
+		beforeReviews, err := p.GetBinaryReviewResults(cmd.Context(), storeExt.Id, binary.Id)
+		if err != nil {
+			return summary.fail("code review", err)
+		}
+
+		if err := p.TriggerCodeReview(cmd.Context(), storeExt.Id); err != nil {
+			return summary.fail("code review", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Waiting up to %s for the code review result", reviewTimeout)
+
+		report, err := p.WaitForCodeReviewReport(cmd.Context(), storeExt.Id, binary.Id, len(beforeReviews), reviewTimeout)
+		if err != nil {
+			return summary.fail("code review", err)
+		}
+
+		if report.Status == accountApi.CodeReviewStatusFailed || report.Status == accountApi.CodeReviewStatusTimeout {
+			summary.add("code review", releaseStepFailed, report.Status)
+		} else {
+			summary.add("code review", releaseStepDone, report.Status)
+		}
+
+		return accountApi.FinishCodeReview(cmd.Context(), report, reviewOutput)
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionReleaseCmd)
+	extensionReleaseCmd.Flags().Bool("skip-store-info", false, "Do not update the store information")
+	extensionReleaseCmd.Flags().Bool("skip-review", false, "Do not wait for the code review result")
+	extensionReleaseCmd.Flags().Duration("review-timeout", 10*time.Minute, "Maximum time to wait for the code review result")
+	extensionReleaseCmd.Flags().String("review-output", "", "Write the code review result as JSON to this file, - for stdout")
+	extensionReleaseCmd.Flags().Bool("disable-git", false, "Use the source folder as it is")
+	extensionReleaseCmd.Flags().String("git-commit", "", "Commit Hash / Tag to use")
+	extensionReleaseCmd.Flags().String("output-directory", "", "Output directory for the zip file")
+	extensionReleaseCmd.Flags().String("account", os.Getenv("HAOKE_CLI_ACCOUNT"), "Account profile to use")
+}
@@ -0,0 +1,189 @@
+package extension
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+//go:embed static/store-preview.html
+var storePreviewTemplate string
+
+var extensionStoreCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Store utilities of an extension",
+}
+
+var extensionStorePreviewCmd = &cobra.Command{
+	Use:   "preview [path]",
+	Short: "Renders a local preview of the store page",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputDir, _ := cmd.PersistentFlags().GetString("output")
+		listen, _ := cmd.PersistentFlags().GetString("listen")
+		noServe, _ := cmd.PersistentFlags().GetBool("no-serve")
+
+		extPath, err := filepath.Abs(args[0])
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("cannot open extension: %w", err)
+		}
+
+		previews, err := extension.BuildStorePreviews(ext)
+		if err != nil {
+			return err
+		}
+
+		if outputDir == "" {
+			if outputDir, err = os.MkdirTemp("", "store-preview-"); err != nil {
+				return err
+			}
+		}
+
+		if err := writeStorePreviews(outputDir, previews); err != nil {
+			return err
+		}
+
+		printStorePreviewProblems(previews)
+
+		logging.FromContext(cmd.Context()).Infof("Store preview written to %s", outputDir)
+
+		if noServe {
+			return nil
+		}
+
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           http.FileServer(http.Dir(outputDir)),
+			ReadHeaderTimeout: time.Second,
+		}
+
+		go func() {
+			<-cmd.Context().Done()
+			_ = server.Close()
+		}()
+
+		logging.FromContext(cmd.Context()).Infof("Store preview available at http://%s/%s.html", listen, previews[0].Language)
+
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionStoreCmd)
+	extensionStoreCmd.AddCommand(extensionStorePreviewCmd)
+	extensionStorePreviewCmd.PersistentFlags().String("output", "", "Folder to write the preview to, defaults to a temporary folder")
+	extensionStorePreviewCmd.PersistentFlags().String("listen", "127.0.0.1:8090", "Address to serve the preview on")
+	extensionStorePreviewCmd.PersistentFlags().Bool("no-serve", false, "Only write the preview files")
+}
+
+// writeStorePreviews writes one html page per language and copies the images next to it.
+func writeStorePreviews(outputDir string, previews []extension.StorePreview) error {
+	tpl, err := template.New("store-preview").Parse(storePreviewTemplate)
+	if err != nil {
+		return err
+	}
+
+	for i := range previews {
+		imageDir := filepath.Join(outputDir, "images", previews[i].Language)
+
+		if err := os.MkdirAll(imageDir, os.ModePerm); err != nil {
+			return err
+		}
+
+		for j, image := range previews[i].Images {
+			target := filepath.Join(imageDir, filepath.Base(image.File))
+
+			if err := copyPreviewFile(image.File, target); err != nil {
+				continue
+			}
+
+			previews[i].Images[j].File = filepath.ToSlash(filepath.Join("images", previews[i].Language, filepath.Base(image.File)))
+		}
+	}
+
+	for _, preview := range previews {
+		file, err := os.Create(filepath.Join(outputDir, fmt.Sprintf("%s.html", preview.Language)))
+		if err != nil {
+			return err
+		}
+
+		err = tpl.Execute(file, map[string]interface{}{
+			"Current":  preview,
+			"Previews": previews,
+		})
+
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot render preview of %s: %w", preview.Language, err)
+		}
+	}
+
+	return nil
+}
+
+func copyPreviewFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+
+		return err
+	}
+
+	return out.Close()
+}
+
+func printStorePreviewProblems(previews []extension.StorePreview) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Language", "Problem"})
+	table.SetAutoWrapText(false)
+
+	problems := 0
+
+	for _, preview := range previews {
+		for _, problem := range preview.Problems {
+			table.Append([]string{preview.Language, problem})
+			problems++
+		}
+	}
+
+	if problems > 0 {
+		table.Render()
+	}
+}
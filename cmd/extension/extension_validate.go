@@ -39,23 +39,13 @@ var extensionValidateCmd = &cobra.Command{
 			return fmt.Errorf("cannot open extension: %w", err)
 		}
 
-		context := extension.RunValidation(cmd.Context(), ext)
-
-		if context.HasErrors() || context.HasWarnings() {
-			table := tablewriter.NewWriter(os.Stdout)
-			table.SetHeader([]string{"Type", "Message"})
-			table.SetAutoWrapText(false)
-
-			for _, msg := range context.Errors() {
-				table.Append([]string{"Error", msg})
-			}
+		if err := extension.RunBeforeValidateHooks(cmd.Context(), ext, false); err != nil {
+			return fmt.Errorf("before hooks validate: %w", err)
+		}
 
-			for _, msg := range context.Warnings() {
-				table.Append([]string{"Warning", msg})
-			}
+		context := extension.RunValidation(cmd.Context(), ext)
 
-			table.Render()
-		}
+		printValidationResult(context)
 
 		if context.HasErrors() {
 			return fmt.Errorf("validation failed")
@@ -70,3 +60,23 @@ var extensionValidateCmd = &cobra.Command{
 func init() {
 	extensionRootCmd.AddCommand(extensionValidateCmd)
 }
+
+func printValidationResult(context *extension.ValidationContext) {
+	if !context.HasErrors() && !context.HasWarnings() {
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Type", "Message"})
+	table.SetAutoWrapText(false)
+
+	for _, msg := range context.Errors() {
+		table.Append([]string{"Error", msg})
+	}
+
+	for _, msg := range context.Warnings() {
+		table.Append([]string{"Warning", msg})
+	}
+
+	table.Render()
+}
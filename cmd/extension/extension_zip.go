@@ -1,16 +1,16 @@
 package extension
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
-	"path"
 	"path/filepath"
 
 	cp "github.com/otiai10/copy"
 	"github.com/spf13/cobra"
 
 	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/internal/hook"
 	"github.com/haokeyingxiao/haoke-cli/logging"
 )
 
@@ -39,158 +39,233 @@ var extensionZipCmd = &cobra.Command{
 			return fmt.Errorf("detect extension type: %w", err)
 		}
 
-		extCfg := ext.GetExtensionConfig()
+		gitCommit, _ := cmd.Flags().GetString("git-commit")
+		outputDir, _ := cmd.Flags().GetString("output-directory")
+		sbom, _ := cmd.Flags().GetBool("sbom")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		options := extensionZipOptions{
+			Branch:     branch,
+			DisableGit: disableGit,
+			GitCommit:  gitCommit,
+			Release:    extensionReleaseMode,
+			OutputDir:  outputDir,
+			SBOM:       sbom,
+		}
 
-		name, err := ext.GetName()
-		if err != nil {
-			return fmt.Errorf("get name: %w", err)
+		if dryRun {
+			return printExtensionZipPlan(cmd.Context(), ext, options)
 		}
 
-		// Clear previous zips
-		existingFiles, err := filepath.Glob(fmt.Sprintf("%s-*.zip", name))
+		_, err = zipExtension(cmd.Context(), ext, options)
+
+		return err
+	},
+}
+
+type extensionZipOptions struct {
+	Branch     string
+	DisableGit bool
+	GitCommit  string
+	Release    bool
+	OutputDir  string
+	SBOM       bool
+}
+
+// zipExtension builds the zip of the extension and returns the path of the created file.
+func zipExtension(ctx context.Context, ext extension.Extension, options extensionZipOptions) (string, error) {
+	extPath := ext.GetPath()
+	extCfg := ext.GetExtensionConfig()
+
+	name, err := ext.GetName()
+	if err != nil {
+		return "", fmt.Errorf("get name: %w", err)
+	}
+
+	// Clear previous zips and their checksums
+	for _, pattern := range []string{"%s-*.zip", "%s-*.zip.sha256"} {
+		existingFiles, err := filepath.Glob(filepath.Join(options.OutputDir, fmt.Sprintf(pattern, name)))
 		if err != nil {
-			return err
+			return "", err
 		}
 
 		for _, file := range existingFiles {
-			err = os.Remove(file)
-			if err != nil {
-				return fmt.Errorf("remove existing file: %w", err)
+			if err := os.Remove(file); err != nil {
+				return "", fmt.Errorf("remove existing file: %w", err)
 			}
 		}
+	}
 
-		// Create temp dir
-		tempDir, err := os.MkdirTemp("", "extension")
-		if err != nil {
-			return fmt.Errorf("create temp directory: %w", err)
-		}
+	// Create temp dir
+	tempDir, err := os.MkdirTemp("", "extension")
+	if err != nil {
+		return "", fmt.Errorf("create temp directory: %w", err)
+	}
 
-		extName, err := ext.GetName()
-		if err != nil {
-			return fmt.Errorf("get extension name: %w", err)
-		}
+	extName, err := ext.GetName()
+	if err != nil {
+		return "", fmt.Errorf("get extension name: %w", err)
+	}
 
-		extDir := fmt.Sprintf("%s/%s/", tempDir, extName)
+	extDir := fmt.Sprintf("%s/%s/", tempDir, extName)
 
-		err = os.Mkdir(extDir, os.ModePerm)
+	err = os.Mkdir(extDir, os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("create temp directory: %w", err)
+	}
+
+	tempDir += "/"
+
+	defer func(path string) {
+		_ = os.RemoveAll(path)
+	}(tempDir)
+
+	var tag string
+
+	// Extract files using strategy
+	if options.DisableGit {
+		err = cp.Copy(extPath, extDir, copyOptions())
 		if err != nil {
-			return fmt.Errorf("create temp directory: %w", err)
+			return "", fmt.Errorf("copy files: %w", err)
+		}
+	} else {
+		tag, err = extension.GitCopyFolder(extPath, extDir, options.GitCommit)
+		if err != nil {
+			return "", fmt.Errorf("copy via git: %w", err)
 		}
 
-		tempDir += "/"
-
-		defer func(path string) {
-			_ = os.RemoveAll(path)
-		}(tempDir)
+		logging.FromContext(ctx).Infof("Checking out %s using Git", tag)
+	}
 
-		var tag string
+	// User input wins
+	if len(options.Branch) > 0 {
+		tag = options.Branch
+	}
 
-		// Extract files using strategy
-		if disableGit {
-			err = cp.Copy(extPath, extDir, copyOptions())
-			if err != nil {
-				return fmt.Errorf("copy files: %w", err)
-			}
-		} else {
-			gitCommit, _ := cmd.Flags().GetString("git-commit")
+	fileName := extension.ZipFileName(name, tag, options.OutputDir)
 
-			tag, err = extension.GitCopyFolder(extPath, extDir, gitCommit)
-			if err != nil {
-				return fmt.Errorf("copy via git: %w", err)
-			}
+	// the hooks run inside the extension folder
+	absFileName, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", err
+	}
 
-			logging.FromContext(cmd.Context()).Infof("Checking out %s using Git", tag)
+	// the environment is only resolved when a stage has hooks, as it looks up the target core version
+	var hookEnv map[string]string
+	runHooks := func(stage string, hooks []hook.Hook) error {
+		if len(hooks) == 0 {
+			return nil
 		}
 
-		// User input wins
-		if len(branch) > 0 {
-			tag = branch
+		if hookEnv == nil {
+			hookEnv = extension.HookEnv(ctx, ext, extDir, absFileName)
 		}
 
-		if extCfg.Build.Zip.Composer.Enabled {
-			if err := executeHooks(ext, extCfg.Build.Zip.Composer.BeforeHooks, extDir); err != nil {
-				return fmt.Errorf("before hooks composer: %w", err)
-			}
+		return hook.Run(ctx, hooks, hook.Context{Stage: stage, Dir: extDir, Release: options.Release, Env: hookEnv})
+	}
 
-			if err := extension.PrepareFolderForZipping(cmd.Context(), extDir, ext, extCfg); err != nil {
-				return fmt.Errorf("prepare package: %w", err)
-			}
+	if extCfg.Build.Zip.Composer.Enabled {
+		if err := runHooks(hook.StageBeforeComposer, extCfg.Build.Zip.Composer.BeforeHooks); err != nil {
+			return "", fmt.Errorf("before hooks composer: %w", err)
+		}
 
-			if err := executeHooks(ext, extCfg.Build.Zip.Composer.AfterHooks, extDir); err != nil {
-				return fmt.Errorf("after hooks composer: %w", err)
-			}
+		if err := extension.PrepareFolderForZipping(ctx, extDir, ext, extCfg); err != nil {
+			return "", fmt.Errorf("prepare package: %w", err)
 		}
 
-		if extCfg.Build.Zip.Assets.Enabled {
-			if err := executeHooks(ext, extCfg.Build.Zip.Assets.BeforeHooks, extDir); err != nil {
-				return fmt.Errorf("before hooks assets: %w", err)
-			}
+		if err := runHooks(hook.StageAfterComposer, extCfg.Build.Zip.Composer.AfterHooks); err != nil {
+			return "", fmt.Errorf("after hooks composer: %w", err)
+		}
+	}
 
-			var tempExt extension.Extension
-			if tempExt, err = extension.GetExtensionByFolder(extDir); err != nil {
-				return err
-			}
+	if extCfg.Build.Zip.Assets.Enabled {
+		if err := runHooks(hook.StageBeforeAssets, extCfg.Build.Zip.Assets.BeforeHooks); err != nil {
+			return "", fmt.Errorf("before hooks assets: %w", err)
+		}
 
-			shopwareConstraint, err := tempExt.GetShopwareVersionConstraint()
-			if err != nil {
-				return fmt.Errorf("get haoke version constraint: %w", err)
-			}
+		var tempExt extension.Extension
+		if tempExt, err = extension.GetExtensionByFolder(extDir); err != nil {
+			return "", err
+		}
 
-			assetBuildConfig := extension.AssetBuildConfig{
-				CleanupNodeModules: true,
-				ShopwareRoot:       os.Getenv("SHOPWARE_PROJECT_ROOT"),
-				ShopwareVersion:    shopwareConstraint,
-			}
+		shopwareConstraint, err := tempExt.GetShopwareVersionConstraint()
+		if err != nil {
+			return "", fmt.Errorf("get haoke version constraint: %w", err)
+		}
 
-			if err := extension.BuildAssetsForExtensions(cmd.Context(), extension.ConvertExtensionsToSources(cmd.Context(), []extension.Extension{tempExt}), assetBuildConfig); err != nil {
-				return fmt.Errorf("building assets: %w", err)
-			}
+		assetBuildConfig := extension.AssetBuildConfig{
+			CleanupNodeModules: true,
+			ShopwareRoot:       os.Getenv("SHOPWARE_PROJECT_ROOT"),
+			ShopwareVersion:    shopwareConstraint,
+		}
 
-			if err := executeHooks(ext, extCfg.Build.Zip.Assets.AfterHooks, extDir); err != nil {
-				return fmt.Errorf("after hooks assets: %w", err)
-			}
+		if err := extension.BuildAssetsForExtensions(ctx, extension.ConvertExtensionsToSources(ctx, []extension.Extension{tempExt}), assetBuildConfig); err != nil {
+			return "", fmt.Errorf("building assets: %w", err)
 		}
 
-		// Cleanup not wanted files
-		if err := extension.CleanupExtensionFolder(extDir, extCfg.Build.Zip.Pack.Excludes.Paths); err != nil {
-			return fmt.Errorf("cleanup package: %w", err)
+		if err := runHooks(hook.StageAfterAssets, extCfg.Build.Zip.Assets.AfterHooks); err != nil {
+			return "", fmt.Errorf("after hooks assets: %w", err)
 		}
+	}
 
-		if extensionReleaseMode {
-			if err := extension.PrepareExtensionForRelease(cmd.Context(), extPath, extDir, ext); err != nil {
-				return fmt.Errorf("prepare for release: %w", err)
-			}
+	// Cleanup not wanted files
+	if err := extension.CleanupExtensionFolder(extDir, extCfg.Build.Zip.Pack.Excludes.Paths); err != nil {
+		return "", fmt.Errorf("cleanup package: %w", err)
+	}
+
+	if options.Release {
+		if err := extension.PrepareExtensionForRelease(ctx, extPath, extDir, ext); err != nil {
+			return "", fmt.Errorf("prepare for release: %w", err)
 		}
+	}
 
-		fileName := fmt.Sprintf("%s-%s.zip", name, tag)
-		if len(tag) == 0 {
-			fileName = fmt.Sprintf("%s.zip", name)
+	if len(options.OutputDir) > 0 {
+		if _, err := os.Stat(options.OutputDir); os.IsNotExist(err) {
+			if err := os.MkdirAll(options.OutputDir, os.ModePerm); err != nil {
+				return "", fmt.Errorf("create output directory: %w", err)
+			}
 		}
+	}
 
-		outputDir, _ := cmd.Flags().GetString("output-directory")
+	if err := runHooks(hook.StageBeforePack, extCfg.Build.Zip.Pack.BeforeHooks); err != nil {
+		return "", fmt.Errorf("before hooks pack: %w", err)
+	}
 
-		if len(outputDir) > 0 {
-			if _, err := os.Stat(outputDir); os.IsNotExist(err) {
-				if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
-					return fmt.Errorf("create output directory: %w", err)
-				}
-			}
+	reference := options.GitCommit
+	if reference == "" {
+		reference = "HEAD"
+	}
 
-			fileName = path.Join(outputDir, fileName)
-		}
+	modified := extension.ZipModifiedTime(extPath, reference)
 
-		if err := executeHooks(ext, extCfg.Build.Zip.Pack.BeforeHooks, extDir); err != nil {
-			return fmt.Errorf("before hooks pack: %w", err)
+	if options.SBOM || extCfg.Build.Zip.Pack.SBOM {
+		sbom, err := extension.GenerateSBOM(ext, extDir, modified)
+		if err != nil {
+			return "", fmt.Errorf("generate sbom: %w", err)
 		}
 
-		if err := extension.CreateZip(tempDir, fileName); err != nil {
-			return fmt.Errorf("create zip file: %w", err)
+		if err := os.WriteFile(filepath.Join(extDir, extension.SBOMFileName), sbom, 0o644); err != nil {
+			return "", fmt.Errorf("write sbom: %w", err)
 		}
+	}
+
+	if err := extension.CreateZip(tempDir, fileName, modified); err != nil {
+		return "", fmt.Errorf("create zip file: %w", err)
+	}
 
-		logging.FromContext(cmd.Context()).Infof("Created file %s", fileName)
+	checksumFile, err := extension.WriteChecksumFile(fileName)
+	if err != nil {
+		return "", fmt.Errorf("create checksum file: %w", err)
+	}
 
-		return nil
-	},
+	logging.FromContext(ctx).Infof("Created file %s", fileName)
+	logging.FromContext(ctx).Infof("Created checksum %s", checksumFile)
+
+	if err := runHooks(hook.StageAfterPack, extCfg.Build.Zip.Pack.AfterHooks); err != nil {
+		return "", fmt.Errorf("after hooks pack: %w", err)
+	}
+
+	return fileName, nil
 }
 
 func init() {
@@ -199,27 +274,8 @@ func init() {
 	extensionZipCmd.Flags().BoolVar(&extensionReleaseMode, "release", false, "Release mode (remove app secrets)")
 	extensionZipCmd.Flags().String("output-directory", "", "Output directory for the zip file")
 	extensionZipCmd.Flags().String("git-commit", "", "Commit Hash / Tag to use")
-}
-
-func executeHooks(ext extension.Extension, hooks []string, extDir string) error {
-	env := []string{
-		fmt.Sprintf("EXTENSION_DIR=%s", extDir),
-		fmt.Sprintf("ORIGINAL_EXTENSION_DIR=%s", ext.GetPath()),
-	}
-
-	for _, hook := range hooks {
-		hookCmd := exec.Command("sh", "-c", hook)
-		hookCmd.Stdout = os.Stdout
-		hookCmd.Stderr = os.Stderr
-		hookCmd.Dir = extDir
-		hookCmd.Env = append(os.Environ(), env...)
-		err := hookCmd.Run()
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	extensionZipCmd.Flags().Bool("sbom", false, "Add a CycloneDX SBOM of the bundled dependencies")
+	extensionZipCmd.Flags().Bool("dry-run", false, "Show the packaging plan without building the zip")
 }
 
 func copyOptions() cp.Options {
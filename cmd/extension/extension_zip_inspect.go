@@ -0,0 +1,137 @@
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+var extensionZipInspectCmd = &cobra.Command{
+	Use:   "inspect [zip]",
+	Short: "List the files of a extension zip and flag not allowed files",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputAsJson, _ := cmd.Flags().GetBool("json")
+
+		entries, err := extension.InspectZip(args[0])
+		if err != nil {
+			return err
+		}
+
+		notAllowed := 0
+
+		for _, entry := range entries {
+			if entry.NotAllowed != "" {
+				notAllowed++
+			}
+		}
+
+		if outputAsJson {
+			content, err := json.Marshal(entries)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+		} else {
+			var total uint64
+
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetColWidth(100)
+			table.SetHeader([]string{"Path", "Size", "Compressed", "Problem"})
+
+			for _, entry := range entries {
+				total += entry.Size
+				table.Append([]string{entry.Path, strconv.FormatUint(entry.Size, 10), strconv.FormatUint(entry.CompressedSize, 10), entry.NotAllowed})
+			}
+
+			table.Render()
+
+			logging.FromContext(cmd.Context()).Infof("%d files with %d bytes", len(entries), total)
+		}
+
+		if notAllowed > 0 {
+			return fmt.Errorf("the zip contains %d files which are not allowed", notAllowed)
+		}
+
+		return nil
+	},
+}
+
+var extensionZipDiffCmd = &cobra.Command{
+	Use:   "diff [old-zip] [new-zip]",
+	Short: "Show the changed files and metadata between two extension zips",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputAsJson, _ := cmd.Flags().GetBool("json")
+
+		diff, err := extension.DiffZips(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		if outputAsJson {
+			content, err := json.Marshal(diff)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+
+			return nil
+		}
+
+		if len(diff.Added)+len(diff.Removed)+len(diff.Changed)+len(diff.Metadata) == 0 {
+			logging.FromContext(cmd.Context()).Infof("The zips contain the same files")
+			return nil
+		}
+
+		if len(diff.Added)+len(diff.Removed)+len(diff.Changed) > 0 {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetColWidth(100)
+			table.SetHeader([]string{"Status", "Path", "Size"})
+
+			for _, entry := range diff.Added {
+				table.Append([]string{"added", entry.Path, strconv.FormatUint(entry.Size, 10)})
+			}
+
+			for _, entry := range diff.Removed {
+				table.Append([]string{"removed", entry.Path, strconv.FormatUint(entry.Size, 10)})
+			}
+
+			for _, entry := range diff.Changed {
+				table.Append([]string{"changed", entry.Path, strconv.FormatUint(entry.Size, 10)})
+			}
+
+			table.Render()
+		}
+
+		if len(diff.Metadata) > 0 {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetColWidth(100)
+			table.SetHeader([]string{"File", "Field", "Old", "New"})
+
+			for _, change := range diff.Metadata {
+				table.Append([]string{change.File, change.Field, change.Old, change.New})
+			}
+
+			table.Render()
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	extensionZipCmd.AddCommand(extensionZipInspectCmd)
+	extensionZipCmd.AddCommand(extensionZipDiffCmd)
+	extensionZipInspectCmd.Flags().Bool("json", false, "Output as json")
+	extensionZipDiffCmd.Flags().Bool("json", false, "Output as json")
+}
@@ -0,0 +1,184 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/internal/hook"
+)
+
+// extensionZipPlan describes what zipExtension would do with the given options.
+type extensionZipPlan struct {
+	Source             string
+	FileName           string
+	MinShopwareVersion string
+	Steps              []string
+	Files              []extension.ZipPlanFile
+}
+
+func planExtensionZip(ctx context.Context, ext extension.Extension, options extensionZipOptions) (*extensionZipPlan, error) {
+	extCfg := ext.GetExtensionConfig()
+
+	name, err := ext.GetName()
+	if err != nil {
+		return nil, fmt.Errorf("get name: %w", err)
+	}
+
+	files, tag, err := extension.PlanZipFiles(ext.GetPath(), options.GitCommit, !options.DisableGit, extCfg.Build.Zip.Pack.Excludes.Paths)
+	if err != nil {
+		return nil, fmt.Errorf("list files: %w", err)
+	}
+
+	plan := &extensionZipPlan{Source: "folder " + ext.GetPath(), Files: files}
+
+	if !options.DisableGit {
+		plan.Source = fmt.Sprintf("git archive of %s", tag)
+	}
+
+	if len(options.Branch) > 0 {
+		tag = options.Branch
+	}
+
+	plan.FileName = extension.ZipFileName(name, tag, options.OutputDir)
+
+	addHooks := func(stage string, hooks []hook.Hook) {
+		for _, h := range hooks {
+			if h.Enabled(options.Release) {
+				plan.Steps = append(plan.Steps, fmt.Sprintf("%s hook: %s", stage, h.String()))
+			}
+		}
+	}
+
+	if extCfg.Build.Zip.Composer.Enabled {
+		addHooks(hook.StageBeforeComposer, extCfg.Build.Zip.Composer.BeforeHooks)
+
+		if hasIncludedFile(files, "composer.json") {
+			minVersion, needsReplacements, err := extension.LookupMinShopwareVersion(ctx, ext)
+
+			switch {
+			case err != nil:
+				plan.MinShopwareVersion = fmt.Sprintf("unknown (%s)", err)
+				plan.Steps = append(plan.Steps, "composer install --no-dev with replacements of the core packages")
+			case needsReplacements:
+				plan.MinShopwareVersion = minVersion
+				plan.Steps = append(plan.Steps, fmt.Sprintf("composer install --no-dev with replacements of the core packages of %s", minVersion))
+				plan.Steps = append(plan.Steps, "check the bundled vendor packages for conflicts with the core")
+			default:
+				plan.MinShopwareVersion = minVersion
+				plan.Steps = append(plan.Steps, "composer install skipped, composer replacements are disabled from 6.6 on")
+			}
+
+			if len(extCfg.Build.Zip.Composer.ExcludedPackages) > 0 {
+				plan.Steps = append(plan.Steps, fmt.Sprintf("composer packages excluded: %v", extCfg.Build.Zip.Composer.ExcludedPackages))
+			}
+		}
+
+		addHooks(hook.StageAfterComposer, extCfg.Build.Zip.Composer.AfterHooks)
+	}
+
+	if extCfg.Build.Zip.Assets.Enabled {
+		addHooks(hook.StageBeforeAssets, extCfg.Build.Zip.Assets.BeforeHooks)
+
+		plan.Steps = append(plan.Steps, fmt.Sprintf("build assets (administration: %s, storefront: %s)",
+			assetBuilder(extCfg.Build.Zip.Assets.EnableESBuildForAdmin),
+			assetBuilder(extCfg.Build.Zip.Assets.EnableESBuildForStorefront)))
+
+		addHooks(hook.StageAfterAssets, extCfg.Build.Zip.Assets.AfterHooks)
+	}
+
+	plan.Steps = append(plan.Steps, "remove not allowed files and pack excludes")
+
+	if options.Release {
+		if extCfg.Changelog.Enabled {
+			plan.Steps = append(plan.Steps, "generate changelog files")
+		}
+
+		if ext.GetType() != "plugin" {
+			plan.Steps = append(plan.Steps, "remove the app secret from manifest.xml")
+		}
+	}
+
+	addHooks(hook.StageBeforePack, extCfg.Build.Zip.Pack.BeforeHooks)
+
+	if options.SBOM || extCfg.Build.Zip.Pack.SBOM {
+		plan.Steps = append(plan.Steps, fmt.Sprintf("write %s", extension.SBOMFileName))
+	}
+
+	plan.Steps = append(plan.Steps, fmt.Sprintf("create %s and %s.sha256", plan.FileName, plan.FileName))
+
+	addHooks(hook.StageAfterPack, extCfg.Build.Zip.Pack.AfterHooks)
+
+	return plan, nil
+}
+
+func printExtensionZipPlan(ctx context.Context, ext extension.Extension, options extensionZipOptions) error {
+	plan, err := planExtensionZip(ctx, ext, options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Source: %s\n", plan.Source)
+	fmt.Printf("Zip: %s\n", plan.FileName)
+
+	if plan.MinShopwareVersion != "" {
+		fmt.Printf("Minimum core version: %s\n", plan.MinShopwareVersion)
+	}
+
+	fmt.Println("\nSteps:")
+
+	for i, step := range plan.Steps {
+		fmt.Printf("%d. %s\n", i+1, step)
+	}
+
+	fmt.Println()
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetColWidth(100)
+	table.SetHeader([]string{"Path", "Status", "Reason"})
+
+	included := 0
+
+	for _, file := range plan.Files {
+		if !file.Excluded {
+			included++
+			table.Append([]string{file.Path, "included", ""})
+
+			continue
+		}
+
+		reason := file.Reason
+		if file.Detail != "" {
+			reason = fmt.Sprintf("%s: %s", file.Reason, file.Detail)
+		}
+
+		table.Append([]string{file.Path, "excluded", reason})
+	}
+
+	table.Render()
+
+	fmt.Printf("%d files included, %d excluded. Files created by composer, asset builds and hooks are not listed.\n", included, len(plan.Files)-included)
+
+	return nil
+}
+
+func hasIncludedFile(files []extension.ZipPlanFile, path string) bool {
+	for _, file := range files {
+		if file.Path == path && !file.Excluded {
+			return true
+		}
+	}
+
+	return false
+}
+
+func assetBuilder(esbuild bool) string {
+	if esbuild {
+		return "esbuild"
+	}
+
+	return "default build"
+}
@@ -12,6 +12,7 @@ import (
 
 	"dario.cat/mergo"
 	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/internal/asset"
 	"github.com/haokeyingxiao/haoke-cli/internal/phpexec"
 	"github.com/haokeyingxiao/haoke-cli/logging"
 	"github.com/haokeyingxiao/haoke-cli/shop"
@@ -61,29 +62,19 @@ var projectCI = &cobra.Command{
 
 		cleanupPaths = append(cleanupPaths, shopCfg.Build.CleanupPaths...)
 
-		logging.FromContext(cmd.Context()).Infof("Installing dependencies using Composer")
+		withDev, _ := cmd.Flags().GetBool("with-dev-dependencies")
+		skipStages, _ := cmd.Flags().GetStringSlice("skip-stage")
 
-		composerFlags := []string{"install", "--no-interaction", "--no-progress", "--optimize-autoloader", "--classmap-authoritative"}
-
-		if withDev, _ := cmd.Flags().GetBool("with-dev-dependencies"); !withDev {
-			composerFlags = append(composerFlags, "--no-dev")
-		}
-
-		token, err := prepareComposerAuth(cmd.Context())
+		stages, err := newCIStageRunner(args[0], !withDev, shopCfg.Build, skipStages)
 		if err != nil {
 			return err
 		}
 
-		composer := phpexec.ComposerCommand(cmd.Context(), composerFlags...)
-		composer.Dir = args[0]
-		composer.Stdin = os.Stdin
-		composer.Stdout = os.Stdout
-		composer.Stderr = os.Stderr
-		composer.Env = append(os.Environ(),
-			"COMPOSER_AUTH="+token,
-		)
+		defer stages.render(os.Stdout)
 
-		if err := composer.Run(); err != nil {
+		if err := stages.run(cmd.Context(), ciStageComposer, func() error {
+			return ciInstallComposerDependencies(cmd.Context(), args[0], withDev)
+		}); err != nil {
 			return err
 		}
 
@@ -91,122 +82,168 @@ var projectCI = &cobra.Command{
 
 		sources := extension.FindAssetSourcesOfProject(cmd.Context(), args[0], shopCfg)
 
-		shopwareConstraint, err := extension.GetShopwareProjectConstraint(args[0])
-		if err != nil {
-			return err
-		}
+		if err := stages.run(cmd.Context(), ciStageAssets, func() error {
+			shopwareConstraint, err := extension.GetShopwareProjectConstraint(args[0])
+			if err != nil {
+				return err
+			}
 
-		assetCfg := extension.AssetBuildConfig{
-			CleanupNodeModules:           true,
-			ShopwareRoot:                 args[0],
-			ShopwareVersion:              shopwareConstraint,
-			Browserslist:                 shopCfg.Build.Browserslist,
-			SkipExtensionsWithBuildFiles: true,
-		}
+			assetCfg := extension.AssetBuildConfig{
+				CleanupNodeModules:           true,
+				ShopwareRoot:                 args[0],
+				ShopwareVersion:              shopwareConstraint,
+				Browserslist:                 shopCfg.Build.Browserslist,
+				SkipExtensionsWithBuildFiles: true,
+			}
 
-		if err := extension.BuildAssetsForExtensions(cmd.Context(), sources, assetCfg); err != nil {
+			return extension.BuildAssetsForExtensions(cmd.Context(), sources, assetCfg)
+		}); err != nil {
 			return err
 		}
 
-		logging.FromContext(cmd.Context()).Infof("Optimizing Administration sources")
-		if err := cleanupAdministrationFiles(cmd.Context(), path.Join(args[0], "vendor", "shopware", "administration")); err != nil {
+		if err := stages.run(cmd.Context(), ciStageCleanup, func() error {
+			return ciCleanup(cmd.Context(), args[0], shopCfg.Build, sources)
+		}); err != nil {
 			return err
 		}
 
-		if err := createEmptySnippetFolder(path.Join(args[0], "vendor", "shopware", "administration")); err != nil {
-			return err
-		}
+		if err := stages.run(cmd.Context(), ciStageWarmup, func() error {
+			logging.FromContext(cmd.Context()).Infof("Warmup container cache")
 
-		if !shopCfg.Build.KeepExtensionSource {
-			for _, source := range sources {
-				if err := cleanupAdministrationFiles(cmd.Context(), source.Path); err != nil {
-					return err
-				}
+			if err := runTransparentCommand(phpexec.PHPCommand(cmd.Context(), path.Join(args[0], "bin", "ci"), "--version")); err != nil { //nolint: gosec
+				return fmt.Errorf("failed to warmup container cache (php bin/ci --version): %w", err)
 			}
+
+			return nil
+		}); err != nil {
+			return err
 		}
 
-		if !shopCfg.Build.KeepSourceMaps {
-			if err := cleanupJavaScriptSourceMaps(path.Join(args[0], "vendor", "shopware", "administration", "Resources", "public")); err != nil {
-				return err
-			}
+		return stages.run(cmd.Context(), ciStageAssetInstall, func() error {
+			return ciInstallAssets(cmd.Context(), args[0], shopCfg.Build, sources)
+		})
+	},
+}
 
-			for _, source := range sources {
-				if err := cleanupJavaScriptSourceMaps(path.Join(source.Path, "Resources", "public")); err != nil {
-					return err
-				}
-			}
-		}
+func ciInstallComposerDependencies(ctx context.Context, root string, withDev bool) error {
+	logging.FromContext(ctx).Infof("Installing dependencies using Composer")
+
+	composerFlags := []string{"install", "--no-interaction", "--no-progress", "--optimize-autoloader", "--classmap-authoritative"}
+
+	if !withDev {
+		composerFlags = append(composerFlags, "--no-dev")
+	}
+
+	token, err := prepareComposerAuth(ctx)
+	if err != nil {
+		return err
+	}
+
+	composer := phpexec.ComposerCommand(ctx, composerFlags...)
+	composer.Dir = root
+	composer.Stdin = os.Stdin
+	composer.Stdout = os.Stdout
+	composer.Stderr = os.Stderr
+	composer.Env = append(os.Environ(),
+		"COMPOSER_AUTH="+token,
+	)
+
+	return composer.Run()
+}
 
-		for _, removePath := range cleanupPaths {
-			logging.FromContext(cmd.Context()).Infof("Removing %s", removePath)
+func ciCleanup(ctx context.Context, root string, buildCfg *shop.ConfigBuild, sources []asset.Source) error {
+	logging.FromContext(ctx).Infof("Optimizing Administration sources")
+	if err := cleanupAdministrationFiles(ctx, path.Join(root, "vendor", "shopware", "administration")); err != nil {
+		return err
+	}
+
+	if err := createEmptySnippetFolder(path.Join(root, "vendor", "shopware", "administration")); err != nil {
+		return err
+	}
 
-			if err := os.RemoveAll(path.Join(args[0], removePath)); err != nil {
+	if !buildCfg.KeepExtensionSource {
+		for _, source := range sources {
+			if err := cleanupAdministrationFiles(ctx, source.Path); err != nil {
 				return err
 			}
 		}
+	}
 
-		if err := cleanupTcpdf(args[0], cmd.Context()); err != nil {
+	if !buildCfg.KeepSourceMaps {
+		if err := cleanupJavaScriptSourceMaps(path.Join(root, "vendor", "shopware", "administration", "Resources", "public")); err != nil {
 			return err
 		}
 
-		logging.FromContext(cmd.Context()).Infof("Warmup container cache")
+		for _, source := range sources {
+			if err := cleanupJavaScriptSourceMaps(path.Join(source.Path, "Resources", "public")); err != nil {
+				return err
+			}
+		}
+	}
 
-		if err := runTransparentCommand(phpexec.PHPCommand(cmd.Context(), path.Join(args[0], "bin", "ci"), "--version")); err != nil { //nolint: gosec
-			return fmt.Errorf("failed to warmup container cache (php bin/ci --version): %w", err)
+	for _, removePath := range cleanupPaths {
+		logging.FromContext(ctx).Infof("Removing %s", removePath)
+
+		if err := os.RemoveAll(path.Join(root, removePath)); err != nil {
+			return err
 		}
+	}
 
-		if !shopCfg.Build.DisableAssetCopy {
-			logging.FromContext(cmd.Context()).Infof("Copying extension assets to final public/bundles folder")
+	return cleanupTcpdf(root, ctx)
+}
 
-			// Delete asset manifest to force a new build
-			manifestPath := path.Join(args[0], "public", "asset-manifest.json")
-			if _, err := os.Stat(manifestPath); err == nil {
-				if err := os.Remove(manifestPath); err != nil {
-					return err
-				}
-			}
+func ciInstallAssets(ctx context.Context, root string, buildCfg *shop.ConfigBuild, sources []asset.Source) error {
+	if !buildCfg.DisableAssetCopy {
+		logging.FromContext(ctx).Infof("Copying extension assets to final public/bundles folder")
 
-			if err := runTransparentCommand(phpexec.PHPCommand(cmd.Context(), path.Join(args[0], "bin", "ci"), "asset:install")); err != nil { //nolint: gosec
-				return fmt.Errorf("failed to install assets (php bin/ci asset:install): %w", err)
+		// Delete asset manifest to force a new build
+		manifestPath := path.Join(root, "public", "asset-manifest.json")
+		if _, err := os.Stat(manifestPath); err == nil {
+			if err := os.Remove(manifestPath); err != nil {
+				return err
 			}
 		}
 
-		if shopCfg.Build.RemoveExtensionAssets {
-			logging.FromContext(cmd.Context()).Infof("Deleting assets of extensions")
+		if err := runTransparentCommand(phpexec.PHPCommand(ctx, path.Join(root, "bin", "ci"), "asset:install")); err != nil { //nolint: gosec
+			return fmt.Errorf("failed to install assets (php bin/ci asset:install): %w", err)
+		}
+	}
 
-			for _, source := range sources {
-				if _, err := os.Stat(path.Join(source.Path, "Resources", "public", "administration", "css")); err == nil {
-					if err := os.WriteFile(path.Join(source.Path, "Resources", ".administration-css"), []byte{}, os.ModePerm); err != nil {
-						return err
-					}
-				}
+	if buildCfg.RemoveExtensionAssets {
+		logging.FromContext(ctx).Infof("Deleting assets of extensions")
 
-				if _, err := os.Stat(path.Join(source.Path, "Resources", "public", "administration", "js")); err == nil {
-					if err := os.WriteFile(path.Join(source.Path, "Resources", ".administration-js"), []byte{}, os.ModePerm); err != nil {
-						return err
-					}
+		for _, source := range sources {
+			if _, err := os.Stat(path.Join(source.Path, "Resources", "public", "administration", "css")); err == nil {
+				if err := os.WriteFile(path.Join(source.Path, "Resources", ".administration-css"), []byte{}, os.ModePerm); err != nil {
+					return err
 				}
+			}
 
-				if err := os.RemoveAll(path.Join(source.Path, "Resources", "public")); err != nil {
+			if _, err := os.Stat(path.Join(source.Path, "Resources", "public", "administration", "js")); err == nil {
+				if err := os.WriteFile(path.Join(source.Path, "Resources", ".administration-js"), []byte{}, os.ModePerm); err != nil {
 					return err
 				}
 			}
 
-			if err := os.RemoveAll(path.Join(args[0], "vendor", "shopware", "administration", "Resources", "public")); err != nil {
+			if err := os.RemoveAll(path.Join(source.Path, "Resources", "public")); err != nil {
 				return err
 			}
+		}
 
-			if err := os.WriteFile(path.Join(args[0], "vendor", "shopware", "administration", "Resources", ".administration-js"), []byte{}, os.ModePerm); err != nil {
-				return err
-			}
+		if err := os.RemoveAll(path.Join(root, "vendor", "shopware", "administration", "Resources", "public")); err != nil {
+			return err
+		}
 
-			if err := os.WriteFile(path.Join(args[0], "vendor", "shopware", "administration", "Resources", ".administration-css"), []byte{}, os.ModePerm); err != nil {
-				return err
-			}
+		if err := os.WriteFile(path.Join(root, "vendor", "shopware", "administration", "Resources", ".administration-js"), []byte{}, os.ModePerm); err != nil {
+			return err
 		}
 
-		return nil
-	},
+		if err := os.WriteFile(path.Join(root, "vendor", "shopware", "administration", "Resources", ".administration-css"), []byte{}, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func createEmptySnippetFolder(root string) error {
@@ -277,6 +314,7 @@ func prepareComposerAuth(ctx context.Context) (string, error) {
 func init() {
 	projectRootCmd.AddCommand(projectCI)
 	projectCI.PersistentFlags().Bool("with-dev-dependencies", false, "Install dev dependencies")
+	projectCI.PersistentFlags().StringSlice("skip-stage", []string{}, fmt.Sprintf("Stages to skip: %s", strings.Join(ciStages, ", ")))
 }
 
 func commandWithRoot(cmd *exec.Cmd, root string) *exec.Cmd {
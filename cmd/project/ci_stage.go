@@ -0,0 +1,130 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/hook"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+	"github.com/haokeyingxiao/haoke-cli/shop"
+)
+
+const (
+	ciStageComposer     = "composer"
+	ciStageAssets       = "assets"
+	ciStageCleanup      = "cleanup"
+	ciStageWarmup       = "warmup"
+	ciStageAssetInstall = "asset_install"
+)
+
+var ciStages = []string{ciStageComposer, ciStageAssets, ciStageCleanup, ciStageWarmup, ciStageAssetInstall}
+
+type ciStageResult struct {
+	Stage    string
+	Status   string
+	Duration time.Duration
+}
+
+// ciStageRunner executes the stages of project ci with their hooks and measures the time of each stage.
+type ciStageRunner struct {
+	root    string
+	release bool
+	hooks   shop.ConfigBuildHooks
+	skip    map[string]bool
+	results []ciStageResult
+}
+
+func newCIStageRunner(root string, release bool, cfg *shop.ConfigBuild, skipStages []string) (*ciStageRunner, error) {
+	runner := &ciStageRunner{
+		root:    root,
+		release: release,
+		hooks:   cfg.Hooks,
+		skip:    map[string]bool{},
+	}
+
+	for _, stage := range append(append([]string{}, cfg.SkipStages...), skipStages...) {
+		if !isCIStage(stage) {
+			return nil, fmt.Errorf("unknown stage %q, available are %s", stage, strings.Join(ciStages, ", "))
+		}
+
+		runner.skip[stage] = true
+	}
+
+	return runner, nil
+}
+
+func isCIStage(stage string) bool {
+	for _, known := range ciStages {
+		if known == stage {
+			return true
+		}
+	}
+
+	return false
+}
+
+// run executes the before hooks, the stage itself and the after hooks. Skipped stages run no hooks.
+func (r *ciStageRunner) run(ctx context.Context, stage string, fn func() error) error {
+	if r.skip[stage] {
+		logging.FromContext(ctx).Infof("Skipping stage %s", stage)
+		r.results = append(r.results, ciStageResult{Stage: stage, Status: "skipped"})
+
+		return nil
+	}
+
+	start := time.Now()
+	stageHooks := r.hooks.Stage(stage)
+
+	err := hook.Run(ctx, stageHooks.Before, r.hookContext("before_"+stage))
+
+	if err == nil {
+		err = fn()
+	}
+
+	if err == nil {
+		err = hook.Run(ctx, stageHooks.After, r.hookContext("after_"+stage))
+	}
+
+	result := ciStageResult{Stage: stage, Status: "done", Duration: time.Since(start)}
+
+	if err != nil {
+		result.Status = "failed"
+	}
+
+	r.results = append(r.results, result)
+
+	return err
+}
+
+func (r *ciStageRunner) hookContext(stage string) hook.Context {
+	return hook.Context{
+		Stage:   stage,
+		Dir:     r.root,
+		Release: r.release,
+		Env:     map[string]string{"PROJECT_ROOT": r.root},
+	}
+}
+
+func (r *ciStageRunner) render(w io.Writer) {
+	if len(r.results) == 0 {
+		return
+	}
+
+	var total time.Duration
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Stage", "Status", "Duration"})
+
+	for _, result := range r.results {
+		total += result.Duration
+		table.Append([]string{result.Stage, result.Status, result.Duration.Round(time.Millisecond).String()})
+	}
+
+	table.Append([]string{"total", "", total.Round(time.Millisecond).String()})
+	table.Render()
+}
@@ -1,10 +1,17 @@
 package project
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/hook"
+	"github.com/haokeyingxiao/haoke-cli/shop"
 )
 
 func TestSourceMapCleanup(t *testing.T) {
@@ -48,3 +55,67 @@ func TestSourceMapCleanup(t *testing.T) {
 		assert.Equal(t, "console.log", string(content))
 	})
 }
+
+func TestCIStageRunner(t *testing.T) {
+	root := t.TempDir()
+
+	cfg := &shop.ConfigBuild{
+		SkipStages: []string{ciStageWarmup},
+		Hooks: shop.ConfigBuildHooks{
+			Composer: shop.ConfigBuildStageHooks{
+				Before: []hook.Hook{{Command: `echo "$HOOK_STAGE" >> stages.txt`}},
+				After:  []hook.Hook{{Command: `echo "$HOOK_STAGE" >> stages.txt`}},
+			},
+			Warmup: shop.ConfigBuildStageHooks{
+				Before: []hook.Hook{{Command: "touch warmup.txt"}},
+			},
+			Cleanup: shop.ConfigBuildStageHooks{
+				After: []hook.Hook{{Command: "touch after-cleanup.txt"}},
+			},
+		},
+	}
+
+	runner, err := newCIStageRunner(root, true, cfg, []string{ciStageAssets})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	assert.NoError(t, runner.run(ctx, ciStageComposer, func() error {
+		return os.WriteFile(filepath.Join(root, "stages.txt"), []byte("composer\n"), os.ModePerm)
+	}))
+
+	assert.NoError(t, runner.run(ctx, ciStageAssets, func() error {
+		t.Fatal("skipped stage must not run")
+		return nil
+	}))
+
+	assert.NoError(t, runner.run(ctx, ciStageWarmup, func() error {
+		t.Fatal("skipped stage must not run")
+		return nil
+	}))
+
+	assert.ErrorContains(t, runner.run(ctx, ciStageCleanup, func() error {
+		return fmt.Errorf("cleanup failed")
+	}), "cleanup failed")
+
+	content, err := os.ReadFile(filepath.Join(root, "stages.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "composer\nafter_composer\n", string(content))
+
+	assert.NoFileExists(t, filepath.Join(root, "warmup.txt"))
+	assert.NoFileExists(t, filepath.Join(root, "after-cleanup.txt"))
+
+	var statuses []string
+	for _, result := range runner.results {
+		statuses = append(statuses, result.Stage+":"+result.Status)
+	}
+
+	assert.Equal(t, []string{"composer:done", "assets:skipped", "warmup:skipped", "cleanup:failed"}, statuses)
+
+	var buf bytes.Buffer
+	runner.render(&buf)
+	assert.Contains(t, buf.String(), "| total")
+
+	_, err = newCIStageRunner(root, true, &shop.ConfigBuild{}, []string{"deploy"})
+	assert.ErrorContains(t, err, `unknown stage "deploy"`)
+}
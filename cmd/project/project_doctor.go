@@ -0,0 +1,463 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	adminSdk "github.com/haokeyingxiao/go-haoke-admin-api-sdk"
+	"github.com/joho/godotenv"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/internal/phpexec"
+	"github.com/haokeyingxiao/haoke-cli/shop"
+	"github.com/haokeyingxiao/haoke-cli/version"
+)
+
+const (
+	doctorStatusPass = "pass"
+	doctorStatusWarn = "warn"
+	doctorStatusFail = "fail"
+)
+
+type doctorResult struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type doctorEnvironment struct {
+	ctx         context.Context
+	projectRoot string
+	shopCfg     *shop.Config
+}
+
+type doctorCheck struct {
+	Name string
+	Run  func(env doctorEnvironment) (string, string)
+}
+
+var doctorChecks = []doctorCheck{
+	{Name: "PHP version", Run: checkDoctorPHPVersion},
+	{Name: "PHP extensions", Run: checkDoctorPHPExtensions},
+	{Name: "Composer platform requirements", Run: checkDoctorComposerPlatform},
+	{Name: "Writable directories", Run: checkDoctorWritableDirectories},
+	{Name: "JWT keys", Run: checkDoctorJWTKeys},
+	{Name: "APP_URL", Run: checkDoctorAppURL},
+	{Name: "Admin API", Run: checkDoctorAdminAPI},
+	{Name: "Node.js", Run: checkDoctorNode},
+}
+
+var projectDoctorCmd = &cobra.Command{
+	Use:   "doctor [path]",
+	Short: "Checks the project environment for common problems",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var projectRoot string
+		var err error
+
+		outputAsJson, _ := cmd.Flags().GetBool("json")
+
+		if len(args) > 0 {
+			projectRoot, err = filepath.Abs(args[0])
+		} else {
+			projectRoot, err = findClosestShopwareProject()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		shopCfg, err := shop.ReadConfig(projectConfigPath, true)
+		if err != nil {
+			return err
+		}
+
+		results := runDoctorChecks(doctorEnvironment{ctx: cmd.Context(), projectRoot: projectRoot, shopCfg: shopCfg}, doctorChecks)
+
+		if outputAsJson {
+			content, err := json.Marshal(results)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+		} else {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetColWidth(100)
+			table.SetHeader([]string{"Status", "Check", "Message"})
+
+			for _, result := range results {
+				table.Append([]string{strings.ToUpper(result.Status), result.Check, result.Message})
+			}
+
+			table.Render()
+		}
+
+		for _, result := range results {
+			if result.Status == doctorStatusFail {
+				return fmt.Errorf("project doctor found problems")
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectDoctorCmd)
+	projectDoctorCmd.Flags().Bool("json", false, "Output as json")
+}
+
+func runDoctorChecks(env doctorEnvironment, checks []doctorCheck) []doctorResult {
+	results := make([]doctorResult, 0, len(checks))
+
+	for _, check := range checks {
+		status, message := check.Run(env)
+		results = append(results, doctorResult{Check: check.Name, Status: status, Message: message})
+	}
+
+	return results
+}
+
+type doctorCoreComposerJson struct {
+	Require map[string]string `json:"require"`
+}
+
+func readCoreRequirements(projectRoot string) (map[string]string, error) {
+	possiblePaths := []string{
+		path.Join(projectRoot, "vendor", "haokeyingxiao", "core", "composer.json"),
+		extension.PlatformPath(projectRoot, "Core", "composer.json"),
+	}
+
+	for _, p := range possiblePaths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+
+		var composer doctorCoreComposerJson
+		if err := json.Unmarshal(content, &composer); err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", p, err)
+		}
+
+		return composer.Require, nil
+	}
+
+	return nil, fmt.Errorf("could not find the composer.json of the core, are the composer dependencies installed?")
+}
+
+func checkDoctorPHPVersion(env doctorEnvironment) (string, string) {
+	requirements, err := readCoreRequirements(env.projectRoot)
+	if err != nil {
+		return doctorStatusWarn, err.Error()
+	}
+
+	output, err := phpexec.PHPCommand(env.ctx, "-r", "echo PHP_VERSION;").Output()
+	if err != nil {
+		return doctorStatusFail, fmt.Sprintf("cannot run php: %v", err)
+	}
+
+	return checkPHPVersionRequirement(strings.TrimSpace(string(output)), requirements["php"])
+}
+
+func checkPHPVersionRequirement(phpVersion, requirement string) (string, string) {
+	if requirement == "" {
+		return doctorStatusPass, fmt.Sprintf("PHP %s", phpVersion)
+	}
+
+	constraint, err := version.NewConstraint(requirement)
+	if err != nil {
+		return doctorStatusWarn, fmt.Sprintf("cannot parse PHP requirement %s: %v", requirement, err)
+	}
+
+	v, err := version.NewVersion(phpVersion)
+	if err != nil {
+		return doctorStatusWarn, fmt.Sprintf("cannot parse PHP version %s: %v", phpVersion, err)
+	}
+
+	if !constraint.Check(v) {
+		return doctorStatusFail, fmt.Sprintf("PHP %s does not match the requirement %s of the core", phpVersion, requirement)
+	}
+
+	return doctorStatusPass, fmt.Sprintf("PHP %s matches %s", phpVersion, requirement)
+}
+
+func checkDoctorPHPExtensions(env doctorEnvironment) (string, string) {
+	requirements, err := readCoreRequirements(env.projectRoot)
+	if err != nil {
+		return doctorStatusWarn, err.Error()
+	}
+
+	output, err := phpexec.PHPCommand(env.ctx, "-m").Output()
+	if err != nil {
+		return doctorStatusFail, fmt.Sprintf("cannot run php: %v", err)
+	}
+
+	missing := missingPHPExtensions(string(output), requirements)
+
+	if len(missing) > 0 {
+		return doctorStatusFail, fmt.Sprintf("missing PHP extensions: %s", strings.Join(missing, ", "))
+	}
+
+	return doctorStatusPass, "all required PHP extensions are loaded"
+}
+
+// missingPHPExtensions compares the output of php -m with the ext-* requirements of a composer.json.
+func missingPHPExtensions(phpModules string, requirements map[string]string) []string {
+	loaded := map[string]bool{}
+
+	for _, line := range strings.Split(phpModules, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		loaded[strings.ToLower(line)] = true
+	}
+
+	missing := make([]string, 0)
+
+	for requirement := range requirements {
+		if !strings.HasPrefix(requirement, "ext-") {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimPrefix(requirement, "ext-"))
+
+		if name == "zend-opcache" {
+			name = "zend opcache"
+		}
+
+		if !loaded[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	sort.Strings(missing)
+
+	return missing
+}
+
+type composerPlatformRequirement struct {
+	Name              string `json:"name"`
+	Version           string `json:"version"`
+	Status            string `json:"status"`
+	FailedRequirement *struct {
+		Source     string `json:"source"`
+		Constraint string `json:"constraint"`
+	} `json:"failed_requirement"`
+}
+
+func checkDoctorComposerPlatform(env doctorEnvironment) (string, string) {
+	if _, err := os.Stat(path.Join(env.projectRoot, "vendor")); os.IsNotExist(err) {
+		return doctorStatusWarn, "vendor directory is missing, run composer install"
+	}
+
+	cmd := phpexec.ComposerCommand(env.ctx, "check-platform-reqs", "--no-interaction", "--format=json")
+	cmd.Dir = env.projectRoot
+
+	// composer exits with a non-zero code when requirements are missing, the JSON output is still valid
+	output, err := cmd.Output()
+	if len(output) == 0 && err != nil {
+		return doctorStatusFail, fmt.Sprintf("cannot run composer: %v", err)
+	}
+
+	var requirements []composerPlatformRequirement
+	if err := json.Unmarshal(output, &requirements); err != nil {
+		return doctorStatusWarn, fmt.Sprintf("cannot parse composer output: %v", err)
+	}
+
+	failed := make([]string, 0)
+
+	for _, requirement := range requirements {
+		if requirement.Status == "success" {
+			continue
+		}
+
+		if requirement.FailedRequirement != nil {
+			failed = append(failed, fmt.Sprintf("%s %s (required %s by %s)", requirement.Name, requirement.Status, requirement.FailedRequirement.Constraint, requirement.FailedRequirement.Source))
+		} else {
+			failed = append(failed, fmt.Sprintf("%s %s", requirement.Name, requirement.Status))
+		}
+	}
+
+	if len(failed) > 0 {
+		return doctorStatusFail, strings.Join(failed, ", ")
+	}
+
+	return doctorStatusPass, fmt.Sprintf("%d platform requirements satisfied", len(requirements))
+}
+
+func checkDoctorWritableDirectories(env doctorEnvironment) (string, string) {
+	notWritable := make([]string, 0)
+
+	for _, dir := range []string{"var", "public"} {
+		if err := checkDirectoryWritable(path.Join(env.projectRoot, dir)); err != nil {
+			notWritable = append(notWritable, fmt.Sprintf("%s (%v)", dir, err))
+		}
+	}
+
+	if len(notWritable) > 0 {
+		return doctorStatusFail, fmt.Sprintf("not writable: %s", strings.Join(notWritable, ", "))
+	}
+
+	return doctorStatusPass, "var and public are writable"
+}
+
+func checkDirectoryWritable(dir string) error {
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+
+	if !stat.IsDir() {
+		return fmt.Errorf("not a directory")
+	}
+
+	f, err := os.CreateTemp(dir, ".haoke-cli-doctor-*")
+	if err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(f.Name())
+}
+
+func checkDoctorJWTKeys(env doctorEnvironment) (string, string) {
+	dotEnv := readProjectEnv(env.projectRoot)
+
+	if dotEnv["JWT_PRIVATE_KEY"] != "" && dotEnv["JWT_PUBLIC_KEY"] != "" {
+		return doctorStatusPass, "JWT keys are configured using environment variables"
+	}
+
+	problems := make([]string, 0)
+
+	for _, file := range []string{"private.pem", "public.pem"} {
+		keyPath := path.Join(env.projectRoot, "config", "jwt", file)
+
+		content, err := os.ReadFile(keyPath)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("config/jwt/%s is not readable", file))
+			continue
+		}
+
+		if !strings.Contains(string(content), "-----BEGIN") {
+			problems = append(problems, fmt.Sprintf("config/jwt/%s is not a PEM file", file))
+		}
+	}
+
+	if len(problems) > 0 {
+		if _, ok := dotEnv["APP_SECRET"]; ok && len(problems) == 2 {
+			return doctorStatusWarn, "no JWT keys found, make sure your Shopware version derives them from APP_SECRET or run project generate-jwt"
+		}
+
+		return doctorStatusFail, strings.Join(problems, ", ")
+	}
+
+	return doctorStatusPass, "config/jwt/private.pem and config/jwt/public.pem are readable"
+}
+
+func checkDoctorAppURL(env doctorEnvironment) (string, string) {
+	appURL := readProjectEnv(env.projectRoot)["APP_URL"]
+
+	return compareAppURL(appURL, env.shopCfg.URL)
+}
+
+func compareAppURL(appURL, configURL string) (string, string) {
+	if appURL == "" {
+		return doctorStatusFail, "APP_URL is not set"
+	}
+
+	if configURL == "" {
+		return doctorStatusWarn, fmt.Sprintf("APP_URL is %s, no url configured in %s to compare", appURL, projectConfigPath)
+	}
+
+	if strings.TrimRight(appURL, "/") != strings.TrimRight(configURL, "/") {
+		return doctorStatusWarn, fmt.Sprintf("APP_URL %s does not match url %s of %s", appURL, configURL, projectConfigPath)
+	}
+
+	return doctorStatusPass, fmt.Sprintf("APP_URL matches %s", appURL)
+}
+
+// readProjectEnv reads the Symfony env files in the order of extension.LoadSymfonyEnvFile without touching the process environment.
+func readProjectEnv(projectRoot string) map[string]string {
+	currentEnv := os.Getenv("APP_ENV")
+	if currentEnv == "" {
+		currentEnv = "dev"
+	}
+
+	result := map[string]string{}
+
+	for _, envFile := range []string{".env", ".env.dist", ".env.local", ".env." + currentEnv, ".env." + currentEnv + ".local"} {
+		values, err := godotenv.Read(path.Join(projectRoot, envFile))
+		if err != nil {
+			continue
+		}
+
+		for key, value := range values {
+			result[key] = value
+		}
+	}
+
+	for _, key := range []string{"APP_URL", "APP_SECRET", "JWT_PRIVATE_KEY", "JWT_PUBLIC_KEY"} {
+		if value, ok := os.LookupEnv(key); ok {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+func checkDoctorAdminAPI(env doctorEnvironment) (string, string) {
+	if env.shopCfg.AdminApi == nil {
+		return doctorStatusWarn, fmt.Sprintf("admin_api is not configured in %s", projectConfigPath)
+	}
+
+	client, err := shop.NewShopClient(env.ctx, env.shopCfg)
+	if err != nil {
+		return doctorStatusFail, err.Error()
+	}
+
+	info, resp, err := client.Info.Info(adminSdk.NewApiContext(env.ctx))
+	if err != nil {
+		return doctorStatusFail, err.Error()
+	}
+
+	_ = resp.Body.Close()
+
+	return doctorStatusPass, fmt.Sprintf("authenticated against %s (version %s)", env.shopCfg.URL, info.Version)
+}
+
+func checkDoctorNode(env doctorEnvironment) (string, string) {
+	missing := make([]string, 0)
+
+	for _, binary := range []string{"node", "npm"} {
+		if _, err := exec.LookPath(binary); err != nil {
+			missing = append(missing, binary)
+		}
+	}
+
+	if len(missing) > 0 {
+		return doctorStatusWarn, fmt.Sprintf("%s not found, asset builds will not work", strings.Join(missing, " and "))
+	}
+
+	output, err := exec.CommandContext(env.ctx, "node", "--version").Output()
+	if err != nil {
+		return doctorStatusWarn, fmt.Sprintf("cannot run node: %v", err)
+	}
+
+	return doctorStatusPass, fmt.Sprintf("node %s and npm are available", strings.TrimSpace(string(output)))
+}
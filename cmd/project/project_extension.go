@@ -1,6 +1,17 @@
 package project
 
-import "github.com/spf13/cobra"
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	adminSdk "github.com/haokeyingxiao/go-haoke-admin-api-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
 
 var projectExtensionCmd = &cobra.Command{
 	Use:   "extension",
@@ -10,3 +21,51 @@ var projectExtensionCmd = &cobra.Command{
 func init() {
 	projectRootCmd.AddCommand(projectExtensionCmd)
 }
+
+// projectExtensionDependencies reads the composer dependencies between the extensions of the local project.
+// Outside of a project no dependencies are known and the given order is used.
+func projectExtensionDependencies(ctx context.Context) map[string][]string {
+	projectRoot, err := findClosestShopwareProject()
+	if err != nil {
+		logging.FromContext(ctx).Debugf("Cannot find local project, extension dependencies are not considered: %v", err)
+		return map[string][]string{}
+	}
+
+	return extension.FindExtensionDependencies(logging.DisableLogger(ctx), projectRoot)
+}
+
+// sortExtensionNames orders the names so dependencies come first, or last when reverse is set (deactivation, uninstall).
+func sortExtensionNames(names []string, dependencies map[string][]string, reverse bool) ([]string, error) {
+	sorted, err := extension.SortByDependencies(names, dependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	if reverse {
+		slices.Reverse(sorted)
+	}
+
+	return sorted, nil
+}
+
+// withDependents returns the names including all extensions depending on them matching the filter.
+// Without cascade an error is returned when such dependents exist.
+func withDependents(names []string, dependencies map[string][]string, extensions adminSdk.ExtensionList, cascade bool, filter func(detail *adminSdk.ExtensionDetail) bool) ([]string, error) {
+	dependents := make([]string, 0)
+
+	for _, name := range extension.FindDependents(names, dependencies) {
+		if detail := extensions.GetByName(name); detail != nil && filter(detail) {
+			dependents = append(dependents, name)
+		}
+	}
+
+	if len(dependents) == 0 {
+		return names, nil
+	}
+
+	if !cascade {
+		return nil, fmt.Errorf("the extensions %s depend on %s, use --cascade to include them", strings.Join(dependents, ", "), strings.Join(names, ", "))
+	}
+
+	return append(append([]string{}, names...), dependents...), nil
+}
@@ -32,6 +32,10 @@ var projectExtensionActivateCmd = &cobra.Command{
 			return err
 		}
 
+		if args, err = sortExtensionNames(args, projectExtensionDependencies(cmd.Context()), false); err != nil {
+			return err
+		}
+
 		failed := false
 
 		for _, arg := range args {
@@ -0,0 +1,384 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	adminSdk "github.com/haokeyingxiao/go-haoke-admin-api-sdk"
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+	"github.com/haokeyingxiao/haoke-cli/shop"
+)
+
+const (
+	extensionActionDeactivate = "deactivate"
+	extensionActionUninstall  = "uninstall"
+	extensionActionInstall    = "install"
+	extensionActionUpdate     = "update"
+	extensionActionActivate   = "activate"
+)
+
+// extensionActionOrder is the order in which the lifecycle phases are executed.
+var extensionActionOrder = []string{
+	extensionActionDeactivate,
+	extensionActionUninstall,
+	extensionActionInstall,
+	extensionActionUpdate,
+	extensionActionActivate,
+}
+
+type extensionOperation struct {
+	Action  string
+	Name    string
+	Type    string
+	Details string
+	// Download is set for updates which have to be fetched from the store first.
+	Download bool
+}
+
+var projectExtensionApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Applies the extension state of the project config to the shop",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		var cfg *shop.Config
+		var err error
+
+		autoApprove, _ := cmd.PersistentFlags().GetBool("auto-approve")
+		dryRun, _ := cmd.PersistentFlags().GetBool("dry-run")
+		disableStoreUpdates, _ := cmd.PersistentFlags().GetBool("disable-store-update")
+
+		if cfg, err = shop.ReadConfig(projectConfigPath, false); err != nil {
+			return err
+		}
+
+		if len(cfg.Extensions) == 0 {
+			return fmt.Errorf("no extensions configured in %s", projectConfigPath)
+		}
+
+		client, err := shop.NewShopClient(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+
+		if _, err := client.ExtensionManager.Refresh(adminSdk.NewApiContext(cmd.Context())); err != nil {
+			return err
+		}
+
+		extensions, _, err := client.ExtensionManager.ListAvailableExtensions(adminSdk.NewApiContext(cmd.Context()))
+		if err != nil {
+			return err
+		}
+
+		dependencies := projectExtensionDependencies(cmd.Context())
+
+		operations, problems := planExtensionState(cfg.Extensions, extensions, disableStoreUpdates, dependencies)
+
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				logging.FromContext(cmd.Context()).Error(problem)
+			}
+
+			return fmt.Errorf("cannot apply extension state")
+		}
+
+		if len(operations) == 0 {
+			logging.FromContext(cmd.Context()).Infof("Extensions are up to date")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetColWidth(100)
+		table.SetHeader([]string{"Action", "Extension", "Details"})
+
+		for _, op := range operations {
+			table.Append([]string{op.Action, op.Name, op.Details})
+		}
+
+		table.Render()
+
+		if dryRun {
+			return nil
+		}
+
+		if !autoApprove {
+			p := promptui.Prompt{
+				Label:     "You want to apply these changes to your Shop?",
+				IsConfirm: true,
+			}
+
+			if _, err := p.Run(); err != nil {
+				return err
+			}
+		}
+
+		failures, skipped := applyExtensionOperations(operations, dependencies, func(op extensionOperation) error {
+			if err := runExtensionOperation(cmd.Context(), client, op); err != nil {
+				logging.FromContext(cmd.Context()).Errorf("%s of %s failed with error: %v", op.Action, op.Name, err)
+				return err
+			}
+
+			logging.FromContext(cmd.Context()).Infof("%s %s done", op.Action, op.Name)
+
+			return nil
+		})
+
+		logging.FromContext(cmd.Context()).Infof("Applied %d of %d operations", len(operations)-len(failures)-len(skipped), len(operations))
+
+		if len(failures) > 0 {
+			for _, failure := range failures {
+				logging.FromContext(cmd.Context()).Errorf("Failed: %s", failure)
+			}
+
+			for _, skip := range skipped {
+				logging.FromContext(cmd.Context()).Errorf("Skipped: %s", skip)
+			}
+
+			return fmt.Errorf("%d operations failed, %d skipped", len(failures), len(skipped))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	projectExtensionCmd.AddCommand(projectExtensionApplyCmd)
+	projectExtensionApplyCmd.PersistentFlags().Bool("auto-approve", false, "Skips the manual confirmation")
+	projectExtensionApplyCmd.PersistentFlags().Bool("dry-run", false, "Only show the plan")
+	projectExtensionApplyCmd.PersistentFlags().Bool("disable-store-update", false, "Do not download updates from the store")
+}
+
+// planExtensionState computes the operations to reach the desired state and collects problems which prevent it.
+func planExtensionState(desired map[string]shop.ConfigExtension, remote adminSdk.ExtensionList, disableStoreUpdates bool, dependencies map[string][]string) ([]extensionOperation, []string) {
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	operations := make([]extensionOperation, 0)
+	problems := make([]string, 0)
+
+	for _, name := range names {
+		state := desired[name]
+		detail := remote.GetByName(name)
+
+		if detail == nil {
+			problems = append(problems, fmt.Sprintf("extension %s is not available in the shop", name))
+			continue
+		}
+
+		isInstalled := detail.InstalledAt != nil
+		isActive := detail.Active
+
+		wantInstalled := isInstalled
+		if state.Installed != nil {
+			wantInstalled = *state.Installed
+		} else if state.Active != nil && *state.Active {
+			wantInstalled = true
+		}
+
+		wantActive := isActive && wantInstalled
+		if state.Active != nil {
+			wantActive = *state.Active
+		}
+
+		if wantActive && !wantInstalled {
+			problems = append(problems, fmt.Sprintf("extension %s cannot be active without being installed", name))
+			continue
+		}
+
+		op := func(action, details string) extensionOperation {
+			return extensionOperation{Action: action, Name: detail.Name, Type: detail.Type, Details: details}
+		}
+
+		if isActive && !wantActive {
+			operations = append(operations, op(extensionActionDeactivate, ""))
+		}
+
+		if isInstalled && !wantInstalled {
+			operations = append(operations, op(extensionActionUninstall, ""))
+		}
+
+		if !isInstalled && wantInstalled {
+			operations = append(operations, op(extensionActionInstall, detail.Version))
+		}
+
+		if state.Version != "" && wantInstalled && state.Version != detail.Version {
+			if state.Version != detail.LatestVersion {
+				problems = append(problems, fmt.Sprintf("extension %s is pinned to %s, but only %s is available", name, state.Version, availableExtensionVersions(detail)))
+				continue
+			}
+
+			update := op(extensionActionUpdate, fmt.Sprintf("%s -> %s", detail.Version, detail.LatestVersion))
+			update.Download = detail.UpdateSource == "store" && !disableStoreUpdates
+			operations = append(operations, update)
+		}
+
+		if !isActive && wantActive {
+			operations = append(operations, op(extensionActionActivate, ""))
+		}
+	}
+
+	problems = append(problems, checkBrokenDependents(operations, remote, dependencies)...)
+
+	ordered, err := orderExtensionOperations(operations, dependencies)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	return ordered, problems
+}
+
+// checkBrokenDependents reports extensions which stay active while an extension they depend on gets deactivated or uninstalled.
+func checkBrokenDependents(operations []extensionOperation, remote adminSdk.ExtensionList, dependencies map[string][]string) []string {
+	removed := make(map[string]bool)
+	activated := make(map[string]bool)
+
+	for _, op := range operations {
+		switch op.Action {
+		case extensionActionDeactivate, extensionActionUninstall:
+			removed[op.Name] = true
+		case extensionActionActivate:
+			activated[op.Name] = true
+		}
+	}
+
+	problems := make([]string, 0)
+
+	for name, deps := range dependencies {
+		if removed[name] {
+			continue
+		}
+
+		detail := remote.GetByName(name)
+		if !activated[name] && (detail == nil || !detail.Active) {
+			continue
+		}
+
+		for _, dep := range deps {
+			if removed[dep] {
+				problems = append(problems, fmt.Sprintf("extension %s depends on %s and would stay active", name, dep))
+			}
+		}
+	}
+
+	sort.Strings(problems)
+
+	return problems
+}
+
+func availableExtensionVersions(detail *adminSdk.ExtensionDetail) string {
+	if detail.IsUpdateAble() {
+		return fmt.Sprintf("%s and %s", detail.Version, detail.LatestVersion)
+	}
+
+	return detail.Version
+}
+
+// orderExtensionOperations groups the operations by lifecycle phase, so for example all installations happen before activations.
+// Inside a phase dependencies come first, for deactivation and uninstall the dependents come first.
+func orderExtensionOperations(operations []extensionOperation, dependencies map[string][]string) ([]extensionOperation, error) {
+	ordered := make([]extensionOperation, 0, len(operations))
+
+	for _, action := range extensionActionOrder {
+		byName := make(map[string]extensionOperation)
+		names := make([]string, 0)
+
+		for _, op := range operations {
+			if op.Action == action {
+				byName[op.Name] = op
+				names = append(names, op.Name)
+			}
+		}
+
+		reverse := action == extensionActionDeactivate || action == extensionActionUninstall
+
+		sorted, err := sortExtensionNames(names, dependencies, reverse)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range sorted {
+			ordered = append(ordered, byName[name])
+		}
+	}
+
+	return ordered, nil
+}
+
+// applyExtensionOperations runs the operations in order and skips the ones depending on a failed or skipped operation.
+// Installation, update and activation wait for the dependencies of the extension, deactivation and uninstall for its dependents.
+func applyExtensionOperations(operations []extensionOperation, dependencies map[string][]string, run func(op extensionOperation) error) ([]string, []string) {
+	failures := make([]string, 0)
+	skipped := make([]string, 0)
+	blocked := make(map[string]string)
+
+	for _, op := range operations {
+		related := dependencies[op.Name]
+		if op.Action == extensionActionDeactivate || op.Action == extensionActionUninstall {
+			related = extension.FindDependents([]string{op.Name}, dependencies)
+		}
+
+		reason, isBlocked := blocked[op.Name]
+
+		for _, name := range related {
+			if isBlocked {
+				break
+			}
+
+			if _, ok := blocked[name]; ok {
+				reason, isBlocked = fmt.Sprintf("%s is not applied", name), true
+			}
+		}
+
+		if isBlocked {
+			skipped = append(skipped, fmt.Sprintf("%s %s: %s", op.Action, op.Name, reason))
+			blocked[op.Name] = reason
+
+			continue
+		}
+
+		if err := run(op); err != nil {
+			failures = append(failures, fmt.Sprintf("%s %s: %v", op.Action, op.Name, err))
+			blocked[op.Name] = fmt.Sprintf("%s of %s failed", op.Action, op.Name)
+		}
+	}
+
+	return failures, skipped
+}
+
+func runExtensionOperation(ctx context.Context, client *adminSdk.Client, op extensionOperation) error {
+	apiCtx := adminSdk.NewApiContext(ctx)
+
+	var err error
+
+	switch op.Action {
+	case extensionActionDeactivate:
+		_, err = client.ExtensionManager.DeactivateExtension(apiCtx, op.Type, op.Name)
+	case extensionActionUninstall:
+		_, err = client.ExtensionManager.UninstallExtension(apiCtx, op.Type, op.Name)
+	case extensionActionInstall:
+		_, err = client.ExtensionManager.InstallExtension(apiCtx, op.Type, op.Name)
+	case extensionActionUpdate:
+		if op.Download {
+			if _, err := client.ExtensionManager.DownloadExtension(apiCtx, op.Name); err != nil {
+				return fmt.Errorf("download failed: %w", err)
+			}
+		}
+
+		_, err = client.ExtensionManager.UpdateExtension(apiCtx, op.Type, op.Name)
+	case extensionActionActivate:
+		_, err = client.ExtensionManager.ActivateExtension(apiCtx, op.Type, op.Name)
+	default:
+		err = fmt.Errorf("unknown action %s", op.Action)
+	}
+
+	return err
+}
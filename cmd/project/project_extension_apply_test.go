@@ -0,0 +1,189 @@
+package project
+
+import (
+	"errors"
+	"testing"
+
+	adminSdk "github.com/haokeyingxiao/go-haoke-admin-api-sdk"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/haokeyingxiao/haoke-cli/shop"
+)
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func installedExtension(name, version string, active bool) *adminSdk.ExtensionDetail {
+	detail := &adminSdk.ExtensionDetail{Name: name, Type: "plugin", Version: version, Active: active}
+	detail.InstalledAt = &struct {
+		Date         string `json:"date"`
+		TimezoneType int    `json:"timezone_type"`
+		Timezone     string `json:"timezone"`
+	}{}
+
+	return detail
+}
+
+func actionsOf(operations []extensionOperation) []string {
+	actions := make([]string, 0, len(operations))
+
+	for _, op := range operations {
+		actions = append(actions, op.Action+" "+op.Name)
+	}
+
+	return actions
+}
+
+func TestPlanExtensionState(t *testing.T) {
+	remote := adminSdk.ExtensionList{
+		{Name: "NotInstalled", Type: "plugin", Version: "1.0.0"},
+		installedExtension("Inactive", "1.0.0", false),
+		installedExtension("Active", "1.0.0", true),
+		installedExtension("Outdated", "1.0.0", true),
+	}
+	remote[3].LatestVersion = "1.1.0"
+	remote[3].UpdateSource = "store"
+
+	t.Run("nothing to do", func(t *testing.T) {
+		operations, problems := planExtensionState(map[string]shop.ConfigExtension{
+			"Active":   {Active: boolPtr(true)},
+			"Inactive": {Installed: boolPtr(true)},
+		}, remote, false, nil)
+
+		assert.Empty(t, problems)
+		assert.Empty(t, operations)
+	})
+
+	t.Run("activate installs first", func(t *testing.T) {
+		operations, problems := planExtensionState(map[string]shop.ConfigExtension{
+			"NotInstalled": {Active: boolPtr(true)},
+			"Inactive":     {Active: boolPtr(true)},
+			"Active":       {Installed: boolPtr(false)},
+		}, remote, false, nil)
+
+		assert.Empty(t, problems)
+		assert.Equal(t, []string{
+			"deactivate Active",
+			"uninstall Active",
+			"install NotInstalled",
+			"activate Inactive",
+			"activate NotInstalled",
+		}, actionsOf(operations))
+	})
+
+	t.Run("pinned version", func(t *testing.T) {
+		operations, problems := planExtensionState(map[string]shop.ConfigExtension{
+			"Outdated": {Version: "1.1.0"},
+		}, remote, false, nil)
+
+		assert.Empty(t, problems)
+		assert.Equal(t, []string{"update Outdated"}, actionsOf(operations))
+		assert.True(t, operations[0].Download)
+
+		operations, _ = planExtensionState(map[string]shop.ConfigExtension{
+			"Outdated": {Version: "1.1.0"},
+		}, remote, true, nil)
+		assert.False(t, operations[0].Download)
+	})
+
+	t.Run("problems", func(t *testing.T) {
+		_, problems := planExtensionState(map[string]shop.ConfigExtension{
+			"Missing":  {Active: boolPtr(true)},
+			"Active":   {Version: "2.0.0"},
+			"Inactive": {Installed: boolPtr(false), Active: boolPtr(true)},
+		}, remote, false, nil)
+
+		assert.Len(t, problems, 3)
+	})
+}
+
+func TestPlanExtensionStateDependencies(t *testing.T) {
+	remote := adminSdk.ExtensionList{
+		{Name: "Addon", Type: "plugin", Version: "1.0.0"},
+		{Name: "Base", Type: "plugin", Version: "1.0.0"},
+		installedExtension("ActiveBase", "1.0.0", true),
+		installedExtension("ActiveAddon", "1.0.0", true),
+	}
+
+	dependencies := map[string][]string{
+		"Addon":       {"Base"},
+		"ActiveAddon": {"ActiveBase"},
+	}
+
+	t.Run("dependencies are activated first", func(t *testing.T) {
+		operations, problems := planExtensionState(map[string]shop.ConfigExtension{
+			"Addon": {Active: boolPtr(true)},
+			"Base":  {Active: boolPtr(true)},
+		}, remote, false, dependencies)
+
+		assert.Empty(t, problems)
+		assert.Equal(t, []string{
+			"install Base",
+			"install Addon",
+			"activate Base",
+			"activate Addon",
+		}, actionsOf(operations))
+	})
+
+	t.Run("dependents are deactivated first", func(t *testing.T) {
+		operations, problems := planExtensionState(map[string]shop.ConfigExtension{
+			"ActiveBase":  {Installed: boolPtr(false)},
+			"ActiveAddon": {Active: boolPtr(false)},
+		}, remote, false, dependencies)
+
+		assert.Empty(t, problems)
+		assert.Equal(t, []string{
+			"deactivate ActiveAddon",
+			"deactivate ActiveBase",
+			"uninstall ActiveBase",
+		}, actionsOf(operations))
+	})
+
+	t.Run("active dependent blocks deactivation", func(t *testing.T) {
+		_, problems := planExtensionState(map[string]shop.ConfigExtension{
+			"ActiveBase": {Active: boolPtr(false)},
+		}, remote, false, dependencies)
+
+		assert.Equal(t, []string{"extension ActiveAddon depends on ActiveBase and would stay active"}, problems)
+	})
+}
+
+func TestApplyExtensionOperationsSkipsDependents(t *testing.T) {
+	dependencies := map[string][]string{
+		"Addon":  {"Base"},
+		"Theme":  {"Addon"},
+		"Legacy": {"OldBase"},
+	}
+
+	operations := []extensionOperation{
+		{Action: extensionActionDeactivate, Name: "Legacy"},
+		{Action: extensionActionDeactivate, Name: "OldBase"},
+		{Action: extensionActionInstall, Name: "Base"},
+		{Action: extensionActionInstall, Name: "Addon"},
+		{Action: extensionActionInstall, Name: "Other"},
+		{Action: extensionActionActivate, Name: "Base"},
+		{Action: extensionActionActivate, Name: "Theme"},
+	}
+
+	executed := make([]string, 0)
+
+	failures, skipped := applyExtensionOperations(operations, dependencies, func(op extensionOperation) error {
+		executed = append(executed, op.Action+" "+op.Name)
+
+		if op.Name == "Legacy" || (op.Action == extensionActionInstall && op.Name == "Base") {
+			return errors.New("broken")
+		}
+
+		return nil
+	})
+
+	assert.Equal(t, []string{"deactivate Legacy", "install Base", "install Other"}, executed)
+	assert.Equal(t, []string{"deactivate Legacy: broken", "install Base: broken"}, failures)
+	assert.Equal(t, []string{
+		"deactivate OldBase: Legacy is not applied",
+		"install Addon: Base is not applied",
+		"activate Base: install of Base failed",
+		"activate Theme: Addon is not applied",
+	}, skipped)
+}
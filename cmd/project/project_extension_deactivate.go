@@ -32,6 +32,19 @@ var projectExtensionDeactivateCmd = &cobra.Command{
 			return err
 		}
 
+		cascade, _ := cmd.PersistentFlags().GetBool("cascade")
+		dependencies := projectExtensionDependencies(cmd.Context())
+
+		if args, err = withDependents(args, dependencies, extensions, cascade, func(detail *adminSdk.ExtensionDetail) bool {
+			return detail.Active
+		}); err != nil {
+			return err
+		}
+
+		if args, err = sortExtensionNames(args, dependencies, true); err != nil {
+			return err
+		}
+
 		failed := false
 
 		for _, arg := range args {
@@ -67,4 +80,5 @@ var projectExtensionDeactivateCmd = &cobra.Command{
 
 func init() {
 	projectExtensionCmd.AddCommand(projectExtensionDeactivateCmd)
+	projectExtensionDeactivateCmd.PersistentFlags().Bool("cascade", false, "Also deactivate extensions depending on the given extensions")
 }
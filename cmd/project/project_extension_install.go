@@ -34,6 +34,10 @@ var projectExtensionInstallCmd = &cobra.Command{
 			return err
 		}
 
+		if args, err = sortExtensionNames(args, projectExtensionDependencies(cmd.Context()), false); err != nil {
+			return err
+		}
+
 		failed := false
 
 		for _, arg := range args {
@@ -0,0 +1,40 @@
+package project
+
+import (
+	"testing"
+
+	adminSdk "github.com/haokeyingxiao/go-haoke-admin-api-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDependents(t *testing.T) {
+	extensions := adminSdk.ExtensionList{
+		installedExtension("Base", "1.0.0", true),
+		installedExtension("Addon", "1.0.0", true),
+		installedExtension("InactiveAddon", "1.0.0", false),
+	}
+
+	dependencies := map[string][]string{
+		"Addon":         {"Base"},
+		"InactiveAddon": {"Base"},
+	}
+
+	isActive := func(detail *adminSdk.ExtensionDetail) bool {
+		return detail.Active
+	}
+
+	_, err := withDependents([]string{"Base"}, dependencies, extensions, false, isActive)
+	assert.ErrorContains(t, err, "Addon depend on Base")
+
+	names, err := withDependents([]string{"Base"}, dependencies, extensions, true, isActive)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Base", "Addon"}, names)
+
+	sorted, err := sortExtensionNames(names, dependencies, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Addon", "Base"}, sorted)
+
+	names, err = withDependents([]string{"Addon"}, dependencies, extensions, false, isActive)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Addon"}, names)
+}
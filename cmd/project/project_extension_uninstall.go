@@ -32,6 +32,19 @@ var projectExtensionUninstallCmd = &cobra.Command{
 			return err
 		}
 
+		cascade, _ := cmd.PersistentFlags().GetBool("cascade")
+		dependencies := projectExtensionDependencies(cmd.Context())
+
+		if args, err = withDependents(args, dependencies, extensions, cascade, func(detail *adminSdk.ExtensionDetail) bool {
+			return detail.InstalledAt != nil
+		}); err != nil {
+			return err
+		}
+
+		if args, err = sortExtensionNames(args, dependencies, true); err != nil {
+			return err
+		}
+
 		failed := false
 
 		for _, arg := range args {
@@ -77,4 +90,5 @@ var projectExtensionUninstallCmd = &cobra.Command{
 
 func init() {
 	projectExtensionCmd.AddCommand(projectExtensionUninstallCmd)
+	projectExtensionUninstallCmd.PersistentFlags().Bool("cascade", false, "Also uninstall extensions depending on the given extensions")
 }
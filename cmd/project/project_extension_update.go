@@ -48,6 +48,10 @@ var projectExtensionUpdateCmd = &cobra.Command{
 			}
 		}
 
+		if args, err = sortExtensionNames(args, projectExtensionDependencies(cmd.Context()), false); err != nil {
+			return err
+		}
+
 		for _, arg := range args {
 			extension := extensions.GetByName(arg)
 
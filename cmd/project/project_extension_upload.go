@@ -3,14 +3,11 @@ package project
 import (
 	"archive/zip"
 	"bytes"
-	"context"
-	"encoding/json"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
 	adminSdk "github.com/haokeyingxiao/go-haoke-admin-api-sdk"
 	cp "github.com/otiai10/copy"
@@ -19,7 +16,6 @@ import (
 	"github.com/haokeyingxiao/haoke-cli/extension"
 	"github.com/haokeyingxiao/haoke-cli/logging"
 	"github.com/haokeyingxiao/haoke-cli/shop"
-	"github.com/haokeyingxiao/haoke-cli/version"
 )
 
 var projectExtensionUploadCmd = &cobra.Command{
@@ -66,7 +62,7 @@ var projectExtensionUploadCmd = &cobra.Command{
 		}
 
 		if increaseVersionBeforeUpload {
-			if err := increaseExtensionVersion(cmd.Context(), ext); err != nil {
+			if err := increaseExtensionVersion(ext); err != nil {
 				return err
 			}
 
@@ -140,7 +136,7 @@ var projectExtensionUploadCmd = &cobra.Command{
 
 		var buf bytes.Buffer
 		w := zip.NewWriter(&buf)
-		if err := extension.AddZipFiles(w, ext.GetPath()+"/", name+"/"); err != nil {
+		if err := extension.AddZipFiles(w, ext.GetPath()+"/", name+"/", time.Time{}); err != nil {
 			return fmt.Errorf("uploading extension: %w", err)
 		}
 
@@ -238,115 +234,18 @@ var projectExtensionUploadCmd = &cobra.Command{
 	},
 }
 
-func increaseExtensionVersion(ctx context.Context, ext extension.Extension) error {
-	if ext.GetType() == "app" {
-		manifestPath := fmt.Sprintf("%s/manifest.xml", ext.GetPath())
-		file, err := os.Open(manifestPath)
-		if err != nil {
-			return fmt.Errorf("cannot read manifest file: %w", err)
-		}
-
-		defer func() {
-			if err := file.Close(); err != nil {
-				logging.FromContext(ctx).Errorf("increaseExtensionVersion: %v", err)
-			}
-		}()
-
-		var buf bytes.Buffer
-		decoder := xml.NewDecoder(file)
-		encoder := xml.NewEncoder(&buf)
-
-		for {
-			token, err := decoder.Token()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				logging.FromContext(ctx).Errorf("error getting token: %v\n", err)
-				break
-			}
-
-			if v, ok := token.(xml.StartElement); ok {
-				if v.Name.Local == "version" {
-					var versionStr string
-					if err = decoder.DecodeElement(&versionStr, &v); err != nil {
-						return err
-					}
-
-					ver, err := version.NewVersion(versionStr)
-					if err != nil {
-						return err
-					}
-
-					ver.Increase()
-
-					if err = encoder.EncodeElement(ver.String(), v); err != nil {
-						return err
-					}
-					continue
-				}
-			}
-
-			if err := encoder.EncodeToken(token); err != nil {
-				return err
-			}
-		}
-
-		// must call flush, otherwise some elements will be missing
-		if err := encoder.Flush(); err != nil {
-			return err
-		}
-
-		newManifest := buf.String()
-		newManifest = strings.ReplaceAll(newManifest, "xmlns:_xmlns=\"xmlns\" _xmlns:xsi=", "xmlns:xsi=")
-		newManifest = strings.ReplaceAll(newManifest, "xmlns:_XMLSchema-instance=\"http://www.w3.org/2001/XMLSchema-instance\" _XMLSchema-instance:noNamespaceSchemaLocation=", "xsi:noNamespaceSchemaLocation=")
-
-		if err := os.WriteFile(manifestPath, []byte(newManifest), os.ModePerm); err != nil {
-			return err
-		}
-
-		return nil
-	}
-
-	composerJsonPath := fmt.Sprintf("%s/composer.json", ext.GetPath())
-
-	composerJsonContent, err := os.ReadFile(composerJsonPath)
+func increaseExtensionVersion(ext extension.Extension) error {
+	current, err := ext.GetVersion()
 	if err != nil {
 		return err
 	}
 
-	var composerJson map[string]interface{}
-
-	if err := json.Unmarshal(composerJsonContent, &composerJson); err != nil {
-		return err
-	}
-
-	versionStr, ok := composerJson["version"].(string)
-
-	if !ok {
-		return nil
-	}
-
-	ver, err := version.NewVersion(versionStr)
-	if err != nil {
-		return err
-	}
-
-	ver.Increase()
-
-	composerJson["version"] = ver.String()
-
-	composerJsonContent, err = json.Marshal(composerJson)
-
+	next, err := extension.BumpVersion(current, extension.VersionBumpPatch)
 	if err != nil {
 		return err
 	}
 
-	if err := os.WriteFile(composerJsonPath, composerJsonContent, os.ModePerm); err != nil {
-		return err
-	}
-
-	return nil
+	return extension.WriteExtensionVersion(ext, next)
 }
 
 func init() {
@@ -0,0 +1,155 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	adminSdk "github.com/haokeyingxiao/go-haoke-admin-api-sdk"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/supervisor"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+	"github.com/haokeyingxiao/haoke-cli/shop"
+)
+
+var projectSchedulerCmd = &cobra.Command{
+	Use:   "scheduler",
+	Short: "Runs the scheduled task runner in background",
+	Long:  "Runs scheduled-task:run and restarts it with an exponential backoff when it crashes",
+	RunE: func(cobraCmd *cobra.Command, _ []string) error {
+		isVerbose, _ := cobraCmd.Flags().GetBool("verbose")
+		memoryLimit, _ := cobraCmd.Flags().GetString("memory-limit")
+		timeLimit, _ := cobraCmd.Flags().GetString("time-limit")
+		statusAddr, _ := cobraCmd.Flags().GetString("status-addr")
+		maxBackoff, _ := cobraCmd.Flags().GetDuration("max-backoff")
+		drainTimeout, _ := cobraCmd.Flags().GetDuration("drain-timeout")
+
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		cancelCtx, cancel := context.WithCancel(cobraCmd.Context())
+		defer cancel()
+		cancelOnTermination(cancelCtx, cancel)
+
+		sv := supervisor.New(consoleProcess("scheduler-0", projectRoot, nil, schedulerArgs(memoryLimit, timeLimit, isVerbose)...))
+		sv.MaxBackoff = maxBackoff
+		sv.DrainTimeout = drainTimeout
+
+		logging.FromContext(cancelCtx).Infof("Starting scheduled task runner")
+
+		return runSupervisor(cancelCtx, sv, statusAddr)
+	},
+}
+
+var projectSchedulerListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List all scheduled tasks with their next execution time",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		var cfg *shop.Config
+		var err error
+
+		outputAsJson, _ := cmd.Flags().GetBool("json")
+
+		if cfg, err = shop.ReadConfig(projectConfigPath, false); err != nil {
+			return err
+		}
+
+		client, err := shop.NewShopClient(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+
+		criteria := adminSdk.Criteria{}
+		criteria.Includes = map[string][]string{"scheduled_task": {"id", "name", "status", "runInterval", "lastExecutionTime", "nextExecutionTime"}}
+
+		tasks, resp, err := client.Repository.ScheduledTask.SearchAll(adminSdk.NewApiContext(cmd.Context()), criteria)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				logging.FromContext(cmd.Context()).Errorf("ScheduledTaskList: %v", err)
+			}
+		}()
+
+		sort.SliceStable(tasks.Data, func(i, j int) bool {
+			return tasks.Data[i].NextExecutionTime.Before(tasks.Data[j].NextExecutionTime)
+		})
+
+		if outputAsJson {
+			content, err := json.Marshal(tasks.Data)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetColWidth(100)
+		table.SetHeader([]string{"Name", "Status", "Interval", "Last Execution", "Next Execution"})
+
+		for _, task := range tasks.Data {
+			table.Append([]string{
+				task.Name,
+				task.Status,
+				(time.Duration(task.RunInterval) * time.Second).String(),
+				formatScheduledTaskTime(task.LastExecutionTime),
+				formatScheduledTaskTime(task.NextExecutionTime),
+			})
+		}
+
+		table.Render()
+
+		return nil
+	},
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectSchedulerCmd)
+	projectSchedulerCmd.AddCommand(projectSchedulerListCmd)
+	projectSchedulerCmd.Flags().Bool("verbose", false, "Enable verbose output")
+	projectSchedulerCmd.Flags().String("memory-limit", "", "Memory Limit")
+	projectSchedulerCmd.Flags().String("time-limit", "", "Time Limit")
+	projectSchedulerCmd.Flags().String("status-addr", "", "Address to serve the scheduler status as JSON (e.g. 127.0.0.1:9090)")
+	projectSchedulerCmd.Flags().Duration("max-backoff", time.Minute, "Maximum delay between restarts of a failing scheduler")
+	projectSchedulerCmd.Flags().Duration("drain-timeout", 30*time.Second, "Time the scheduler gets to finish on shutdown")
+	projectSchedulerListCmd.Flags().Bool("json", false, "Output as json")
+}
+
+func schedulerArgs(memoryLimit, timeLimit string, verbose bool) []string {
+	args := []string{"scheduled-task:run"}
+
+	if memoryLimit != "" {
+		args = append(args, fmt.Sprintf("--memory-limit=%s", memoryLimit))
+	}
+
+	if timeLimit != "" {
+		args = append(args, fmt.Sprintf("--time-limit=%s", timeLimit))
+	}
+
+	if verbose {
+		args = append(args, "-vvv")
+	}
+
+	return args
+}
+
+func formatScheduledTaskTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Local().Format(time.DateTime)
+}
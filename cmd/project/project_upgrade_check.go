@@ -0,0 +1,208 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	accountApi "github.com/haokeyingxiao/haoke-cli/account-api"
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+	"github.com/haokeyingxiao/haoke-cli/version"
+)
+
+const (
+	upgradeStatusCompatible = "compatible"
+	upgradeStatusBlocking   = "blocking"
+	// upgradeStatusUpgradeAvailable is used when the installed version is not compatible, but a store update supports the target version.
+	upgradeStatusUpgradeAvailable = "upgrade-available"
+)
+
+type upgradeCheckResult struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	Constraint    string `json:"constraint"`
+	LatestVersion string `json:"latestVersion,omitempty"`
+	Status        string `json:"status"`
+	Message       string `json:"message,omitempty"`
+}
+
+var projectUpgradeCheckCmd = &cobra.Command{
+	Use:   "upgrade-check [target-version] [path]",
+	Short: "Checks if all extensions are compatible with the given Shopware version",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputAsJson, _ := cmd.PersistentFlags().GetBool("json")
+
+		targetVersion, err := version.NewVersion(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid target version: %w", err)
+		}
+
+		var projectRoot string
+
+		if len(args) > 1 {
+			projectRoot, err = filepath.Abs(args[1])
+		} else {
+			projectRoot, err = findClosestShopwareProject()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		extensions := extension.FindExtensionsFromProject(logging.DisableLogger(cmd.Context()), projectRoot)
+
+		type projectExtension struct {
+			name          string
+			version       string
+			constraint    *version.Constraints
+			constraintErr error
+		}
+
+		projectExtensions := make([]projectExtension, 0, len(extensions))
+		incompatible := make([]accountApi.StoreUpdateRequest, 0)
+
+		for _, ext := range extensions {
+			name, err := ext.GetName()
+			if err != nil {
+				continue
+			}
+
+			projectExt := projectExtension{name: name}
+			projectExt.constraint, projectExt.constraintErr = ext.GetShopwareVersionConstraint()
+
+			if extVersion, _ := ext.GetVersion(); extVersion != nil {
+				projectExt.version = extVersion.String()
+			}
+
+			if projectExt.constraintErr == nil && !projectExt.constraint.Check(targetVersion) {
+				incompatible = append(incompatible, accountApi.StoreUpdateRequest{Name: name, Version: projectExt.version})
+			}
+
+			projectExtensions = append(projectExtensions, projectExt)
+		}
+
+		updates := fetchStoreUpdates(cmd.Context(), targetVersion, incompatible)
+
+		results := make([]upgradeCheckResult, 0, len(projectExtensions))
+
+		for _, projectExt := range projectExtensions {
+			result := checkExtensionUpgrade(projectExt.constraint, projectExt.constraintErr, targetVersion, updates[projectExt.name])
+			result.Name = projectExt.name
+			result.Version = projectExt.version
+
+			results = append(results, result)
+		}
+
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].Name < results[j].Name
+		})
+
+		if outputAsJson {
+			content, err := json.Marshal(results)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+		} else {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetColWidth(100)
+			table.SetHeader([]string{"Name", "Version", "Constraint", "Compatible Update", "Status"})
+
+			for _, result := range results {
+				table.Append([]string{result.Name, result.Version, result.Constraint, result.LatestVersion, result.Status})
+			}
+
+			table.Render()
+		}
+
+		blocking := 0
+		upgradeAvailable := 0
+
+		for _, result := range results {
+			switch result.Status {
+			case upgradeStatusBlocking:
+				blocking++
+			case upgradeStatusUpgradeAvailable:
+				upgradeAvailable++
+			}
+		}
+
+		if upgradeAvailable > 0 {
+			logging.FromContext(cmd.Context()).Warnf("%d extensions have to be updated before the upgrade to %s", upgradeAvailable, targetVersion.String())
+		}
+
+		if blocking > 0 {
+			return fmt.Errorf("%d extensions are blocking the upgrade to %s", blocking, targetVersion.String())
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectUpgradeCheckCmd)
+	projectUpgradeCheckCmd.PersistentFlags().Bool("json", false, "Output as json")
+}
+
+// fetchStoreUpdates asks the store for updates of the incompatible extensions supporting the target version, by extension name.
+// When the store is not reachable, no updates are considered.
+func fetchStoreUpdates(ctx context.Context, target *version.Version, extensions []accountApi.StoreUpdateRequest) map[string]*accountApi.StoreUpdate {
+	updates := make(map[string]*accountApi.StoreUpdate)
+
+	if len(extensions) == 0 {
+		return updates
+	}
+
+	storeUpdates, err := accountApi.GetStoreUpdates(ctx, target.String(), extensions)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot fetch extension updates from the store, store updates are not considered: %v", err)
+		return updates
+	}
+
+	for i := range storeUpdates {
+		updates[storeUpdates[i].Name] = &storeUpdates[i]
+	}
+
+	return updates
+}
+
+func checkExtensionUpgrade(constraint *version.Constraints, constraintErr error, target *version.Version, update *accountApi.StoreUpdate) upgradeCheckResult {
+	result := upgradeCheckResult{}
+
+	if constraintErr != nil {
+		result.Status = upgradeStatusBlocking
+		result.Message = constraintErr.Error()
+
+		return result
+	}
+
+	result.Constraint = constraint.String()
+
+	if constraint.Check(target) {
+		result.Status = upgradeStatusCompatible
+
+		return result
+	}
+
+	if update != nil {
+		result.Status = upgradeStatusUpgradeAvailable
+		result.LatestVersion = update.Version
+		result.Message = fmt.Sprintf("the installed version does not support %s, update %s does", target.String(), update.Version)
+
+		return result
+	}
+
+	result.Status = upgradeStatusBlocking
+	result.Message = fmt.Sprintf("%s does not match %s and no store update supports it", target.String(), result.Constraint)
+
+	return result
+}
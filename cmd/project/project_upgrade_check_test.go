@@ -0,0 +1,48 @@
+package project
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	accountApi "github.com/haokeyingxiao/haoke-cli/account-api"
+	"github.com/haokeyingxiao/haoke-cli/version"
+)
+
+func TestCheckExtensionUpgrade(t *testing.T) {
+	target := version.Must(version.NewVersion("6.6.0.0"))
+	constraint := func(c string) *version.Constraints {
+		v := version.MustConstraints(version.NewConstraint(c))
+		return &v
+	}
+
+	t.Run("compatible", func(t *testing.T) {
+		result := checkExtensionUpgrade(constraint("~6.5.0 || ~6.6.0"), nil, target, nil)
+		assert.Equal(t, upgradeStatusCompatible, result.Status)
+	})
+
+	t.Run("blocking", func(t *testing.T) {
+		result := checkExtensionUpgrade(constraint("~6.5.0"), nil, target, nil)
+		assert.Equal(t, upgradeStatusBlocking, result.Status)
+		assert.Equal(t, "~6.5.0", result.Constraint)
+	})
+
+	t.Run("upgrade available with store update", func(t *testing.T) {
+		result := checkExtensionUpgrade(constraint("~6.5.0"), nil, target, &accountApi.StoreUpdate{Name: "FroshTools", Version: "2.0.0"})
+		assert.Equal(t, upgradeStatusUpgradeAvailable, result.Status)
+		assert.Equal(t, "2.0.0", result.LatestVersion)
+	})
+
+	t.Run("compatible ignores store update", func(t *testing.T) {
+		result := checkExtensionUpgrade(constraint("~6.6.0"), nil, target, &accountApi.StoreUpdate{Name: "FroshTools", Version: "2.0.0"})
+		assert.Equal(t, upgradeStatusCompatible, result.Status)
+		assert.Empty(t, result.LatestVersion)
+	})
+
+	t.Run("invalid constraint", func(t *testing.T) {
+		result := checkExtensionUpgrade(nil, fmt.Errorf("require.haokeyingxiao/core is required"), target, nil)
+		assert.Equal(t, upgradeStatusBlocking, result.Status)
+		assert.NotEmpty(t, result.Message)
+	})
+}
@@ -0,0 +1,217 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/haokeyingxiao/haoke-cli/extension"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+type projectValidationResult struct {
+	Name     string   `json:"name"`
+	Path     string   `json:"path"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+var projectValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Validates all extensions of the project",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var projectRoot string
+		var err error
+
+		outputAsJson, _ := cmd.PersistentFlags().GetBool("json")
+		includeVendor, _ := cmd.PersistentFlags().GetBool("include-vendor")
+		onlyExtensions, _ := cmd.PersistentFlags().GetString("only-extensions")
+		skipExtensions, _ := cmd.PersistentFlags().GetString("skip-extensions")
+
+		if onlyExtensions != "" && skipExtensions != "" {
+			return fmt.Errorf("only-extensions and skip-extensions cannot be used together")
+		}
+
+		if len(args) > 0 {
+			projectRoot, err = filepath.Abs(args[0])
+		} else {
+			projectRoot, err = findClosestShopwareProject()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		extensions := extension.FindExtensionsFromProject(logging.DisableLogger(cmd.Context()), projectRoot)
+		extensions = filterProjectExtensions(projectRoot, extensions, includeVendor, splitExtensionList(onlyExtensions), splitExtensionList(skipExtensions))
+
+		if len(extensions) == 0 {
+			return fmt.Errorf("no extensions found to validate")
+		}
+
+		results := make([]projectValidationResult, len(extensions))
+		semaphore := make(chan struct{}, runtime.NumCPU())
+
+		var wg sync.WaitGroup
+
+		for i, ext := range extensions {
+			wg.Add(1)
+
+			go func(i int, ext extension.Extension) {
+				defer wg.Done()
+
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				name, _ := ext.GetName()
+				validation := extension.RunValidation(cmd.Context(), ext)
+
+				results[i] = projectValidationResult{
+					Name:     name,
+					Path:     ext.GetPath(),
+					Errors:   append([]string{}, validation.Errors()...),
+					Warnings: append([]string{}, validation.Warnings()...),
+				}
+			}(i, ext)
+		}
+
+		wg.Wait()
+
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].Name < results[j].Name
+		})
+
+		if outputAsJson {
+			content, err := json.Marshal(results)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+		} else {
+			printProjectValidationReport(projectRoot, results)
+		}
+
+		failed := 0
+
+		for _, result := range results {
+			if len(result.Errors) > 0 {
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("validation failed for %d of %d extensions", failed, len(results))
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Validation of %d extensions has been successful", len(results))
+
+		return nil
+	},
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectValidateCmd)
+	projectValidateCmd.PersistentFlags().Bool("json", false, "Output as json")
+	projectValidateCmd.PersistentFlags().Bool("include-vendor", false, "Also validate extensions installed from vendor packages")
+	projectValidateCmd.PersistentFlags().String("only-extensions", "", "Only validate the given extensions (comma separated)")
+	projectValidateCmd.PersistentFlags().String("skip-extensions", "", "Skips the given extensions (comma separated)")
+}
+
+func splitExtensionList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	list := strings.Split(value, ",")
+
+	for i := range list {
+		list[i] = strings.TrimSpace(list[i])
+	}
+
+	return list
+}
+
+// filterProjectExtensions keeps the extensions living inside the custom folder of the project and applies the name filters.
+func filterProjectExtensions(projectRoot string, extensions []extension.Extension, includeVendor bool, only, skip []string) []extension.Extension {
+	customDir := filepath.Join(projectRoot, "custom")
+
+	if evaluated, err := filepath.EvalSymlinks(customDir); err == nil {
+		customDir = evaluated
+	}
+
+	filtered := make([]extension.Extension, 0, len(extensions))
+
+	for _, ext := range extensions {
+		name, err := ext.GetName()
+		if err != nil {
+			continue
+		}
+
+		if len(only) > 0 && !slices.Contains(only, name) {
+			continue
+		}
+
+		if slices.Contains(skip, name) {
+			continue
+		}
+
+		if !includeVendor {
+			extPath := ext.GetPath()
+
+			if evaluated, err := filepath.EvalSymlinks(extPath); err == nil {
+				extPath = evaluated
+			}
+
+			if !strings.HasPrefix(extPath, customDir+string(filepath.Separator)) {
+				continue
+			}
+		}
+
+		filtered = append(filtered, ext)
+	}
+
+	return filtered
+}
+
+func printProjectValidationReport(projectRoot string, results []projectValidationResult) {
+	for _, result := range results {
+		relPath, err := filepath.Rel(projectRoot, result.Path)
+		if err != nil {
+			relPath = result.Path
+		}
+
+		fmt.Printf("\n%s (%s)\n", result.Name, relPath)
+
+		if len(result.Errors) == 0 && len(result.Warnings) == 0 {
+			fmt.Println("No problems found")
+			continue
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Type", "Message"})
+		table.SetAutoWrapText(false)
+
+		for _, msg := range result.Errors {
+			table.Append([]string{"Error", msg})
+		}
+
+		for _, msg := range result.Warnings {
+			table.Append([]string{"Warning", msg})
+		}
+
+		table.Render()
+	}
+
+	fmt.Println()
+}
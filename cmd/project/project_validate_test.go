@@ -0,0 +1,49 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/haokeyingxiao/haoke-cli/extension"
+)
+
+func createTestApp(t *testing.T, dir, name string) extension.Extension {
+	t.Helper()
+
+	assert.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	assert.NoError(t, os.WriteFile(path.Join(dir, "manifest.xml"), []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<manifest>
+	<meta>
+		<name>%s</name>
+		<label>Label</label>
+		<author>Your Company Ltd.</author>
+		<copyright>(c) by Your Company Ltd.</copyright>
+		<version>1.0.0</version>
+		<license>MIT</license>
+	</meta>
+</manifest>`, name)), os.ModePerm))
+
+	ext, err := extension.GetExtensionByFolder(dir)
+	assert.NoError(t, err)
+
+	return ext
+}
+
+func TestFilterProjectExtensions(t *testing.T) {
+	projectRoot := t.TempDir()
+
+	first := createTestApp(t, path.Join(projectRoot, "custom", "apps", "FirstApp"), "FirstApp")
+	second := createTestApp(t, path.Join(projectRoot, "custom", "apps", "SecondApp"), "SecondApp")
+	vendor := createTestApp(t, path.Join(projectRoot, "vendor", "store", "VendorApp"), "VendorApp")
+
+	all := []extension.Extension{first, second, vendor}
+
+	assert.Equal(t, []extension.Extension{first, second}, filterProjectExtensions(projectRoot, all, false, nil, nil))
+	assert.Equal(t, []extension.Extension{first, second, vendor}, filterProjectExtensions(projectRoot, all, true, nil, nil))
+	assert.Equal(t, []extension.Extension{second}, filterProjectExtensions(projectRoot, all, false, splitExtensionList("SecondApp"), nil))
+	assert.Equal(t, []extension.Extension{second}, filterProjectExtensions(projectRoot, all, false, nil, splitExtensionList("FirstApp, VendorApp")))
+}
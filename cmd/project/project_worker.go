@@ -2,15 +2,18 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"os/exec"
 	"os/signal"
 	"strconv"
 	"strings"
-	"sync"
-	"syscall"
+	"time"
 
 	"github.com/haokeyingxiao/haoke-cli/internal/phpexec"
+	"github.com/haokeyingxiao/haoke-cli/internal/supervisor"
 	"github.com/haokeyingxiao/haoke-cli/shop"
 
 	"github.com/spf13/cobra"
@@ -21,15 +24,23 @@ import (
 var projectWorkerCmd = &cobra.Command{
 	Use:   "worker [amount]",
 	Short: "Runs the Symfony Worker in Background",
+	Long: `Runs the Symfony Worker in Background and restarts crashed workers with an exponential backoff.
+
+The amount of workers can be set per queue using --queue async=3,low_priority=1.
+Use --status-addr to expose the uptime, restarts and last exit code of each worker as JSON.`,
 	RunE: func(cobraCmd *cobra.Command, args []string) error {
 		var projectRoot string
 		var err error
-		workerAmount := 1
+		workerAmount := 0
 
 		isVerbose, _ := cobraCmd.Flags().GetBool("verbose")
 		queuesToConsume, _ := cobraCmd.Flags().GetString("queue")
 		memoryLimit, _ := cobraCmd.Flags().GetString("memory-limit")
 		timeLimit, _ := cobraCmd.Flags().GetString("time-limit")
+		statusAddr, _ := cobraCmd.Flags().GetString("status-addr")
+		maxBackoff, _ := cobraCmd.Flags().GetDuration("max-backoff")
+		drainTimeout, _ := cobraCmd.Flags().GetDuration("drain-timeout")
+		withScheduler, _ := cobraCmd.Flags().GetBool("with-scheduler")
 
 		if projectRoot, err = findClosestShopwareProject(); err != nil {
 			return err
@@ -51,65 +62,188 @@ var projectWorkerCmd = &cobra.Command{
 			timeLimit = "120"
 		}
 
-		cancelCtx, cancel := context.WithCancel(cobraCmd.Context())
-		cancelOnTermination(cancelCtx, cancel)
-
-		consumeArgs := []string{"messenger:consume", fmt.Sprintf("--memory-limit=%s", memoryLimit), fmt.Sprintf("--time-limit=%s", timeLimit)}
-
 		if queuesToConsume == "" {
 			if is, _ := shop.IsShopwareVersion(projectRoot, ">=6.5.7"); is {
-				consumeArgs = append(consumeArgs, "async", "failed", "low_priority")
+				queuesToConsume = "async,failed,low_priority"
 			} else if is, _ := shop.IsShopwareVersion(projectRoot, ">=6.5"); is {
-				consumeArgs = append(consumeArgs, "async", "failed")
+				queuesToConsume = "async,failed"
 			}
-		} else {
-			consumeArgs = append(consumeArgs, strings.Split(queuesToConsume, ",")...)
 		}
 
-		if isVerbose {
-			consumeArgs = append(consumeArgs, "-vvv")
+		groups, err := parseWorkerQueues(queuesToConsume, workerAmount)
+		if err != nil {
+			return err
 		}
 
+		cancelCtx, cancel := context.WithCancel(cobraCmd.Context())
+		defer cancel()
+		cancelOnTermination(cancelCtx, cancel)
+
 		baseName := fmt.Sprintf("shopware-cli-%d", os.Getpid())
 
-		var wg sync.WaitGroup
-		for a := 0; a < workerAmount; a++ {
-			wg.Add(1)
-			go func(ctx context.Context, index int) {
-				for {
-					cmd := phpexec.ConsoleCommand(cancelCtx, consumeArgs...)
-					cmd.Dir = projectRoot
-					cmd.Stdout = os.Stdout
-					cmd.Stderr = os.Stderr
-					cmd.Env = append(os.Environ(), fmt.Sprintf("MESSENGER_CONSUMER_NAME=%s-%d", baseName, index))
-
-					if err := cmd.Run(); err != nil {
-						logging.FromContext(ctx).Fatal(err)
-					}
-				}
-			}(cancelCtx, a)
+		processes := make([]supervisor.Process, 0)
+		for _, group := range groups {
+			consumeArgs := []string{"messenger:consume", fmt.Sprintf("--memory-limit=%s", memoryLimit), fmt.Sprintf("--time-limit=%s", timeLimit)}
+			consumeArgs = append(consumeArgs, group.Queues...)
+
+			if isVerbose {
+				consumeArgs = append(consumeArgs, "-vvv")
+			}
+
+			for i := 0; i < group.Workers; i++ {
+				name := fmt.Sprintf("%s-%d", group.Name(), i)
+				consumerName := fmt.Sprintf("%s-%s", baseName, name)
+
+				processes = append(processes, consoleProcess(name, projectRoot, []string{fmt.Sprintf("MESSENGER_CONSUMER_NAME=%s", consumerName)}, consumeArgs...))
+			}
+		}
+
+		if withScheduler {
+			processes = append(processes, consoleProcess("scheduler-0", projectRoot, nil, schedulerArgs(memoryLimit, timeLimit, isVerbose)...))
 		}
 
-		wg.Wait()
+		sv := supervisor.New(processes...)
+		sv.MaxBackoff = maxBackoff
+		sv.DrainTimeout = drainTimeout
+
+		logging.FromContext(cancelCtx).Infof("Starting %d worker(s)", len(processes))
 
-		return nil
+		return runSupervisor(cancelCtx, sv, statusAddr)
 	},
 }
 
 func init() {
 	projectRootCmd.AddCommand(projectWorkerCmd)
 	projectWorkerCmd.PersistentFlags().Bool("verbose", false, "Enable verbose output")
-	projectWorkerCmd.PersistentFlags().String("queue", "", "Queues to consume")
+	projectWorkerCmd.PersistentFlags().String("queue", "", "Queues to consume, optionally with worker amount per queue (async=3,low_priority=1)")
 	projectWorkerCmd.PersistentFlags().String("memory-limit", "", "Memory Limit")
 	projectWorkerCmd.PersistentFlags().String("time-limit", "", "Time Limit")
+	projectWorkerCmd.PersistentFlags().String("status-addr", "", "Address to serve the worker status as JSON (e.g. 127.0.0.1:9090)")
+	projectWorkerCmd.PersistentFlags().Duration("max-backoff", time.Minute, "Maximum delay between restarts of a failing worker")
+	projectWorkerCmd.PersistentFlags().Duration("drain-timeout", 30*time.Second, "Time workers get to finish their current message on shutdown")
+	projectWorkerCmd.PersistentFlags().Bool("with-scheduler", false, "Additionally run the scheduled task runner")
+}
+
+// consoleProcess creates a supervised bin/console process running inside the project root.
+func consoleProcess(name, projectRoot string, env []string, args ...string) supervisor.Process {
+	return supervisor.Process{
+		Name: name,
+		Command: func(ctx context.Context) *exec.Cmd {
+			cmd := phpexec.ConsoleCommand(ctx, args...)
+			cmd.Dir = projectRoot
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			cmd.Env = append(os.Environ(), env...)
+
+			return cmd
+		},
+	}
+}
+
+// runSupervisor runs the supervisor until the context is cancelled and optionally serves its status on statusAddr.
+func runSupervisor(ctx context.Context, sv *supervisor.Supervisor, statusAddr string) error {
+	if statusAddr != "" {
+		server := &http.Server{
+			Addr:              statusAddr,
+			Handler:           sv.Handler(),
+			ReadHeaderTimeout: time.Second,
+		}
+
+		go func() {
+			logging.FromContext(ctx).Infof("Worker status available at http://%s", statusAddr)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logging.FromContext(ctx).Errorf("cannot start status server: %v", err)
+			}
+		}()
+
+		defer func() {
+			_ = server.Close()
+		}()
+	}
+
+	if err := sv.Run(ctx); err != nil {
+		return err
+	}
+
+	for _, st := range sv.Statuses() {
+		logging.FromContext(ctx).Infof("%s: restarts=%d last_exit_code=%d", st.Name, st.Restarts, st.LastExitCode)
+	}
+
+	return nil
+}
+
+type workerQueueGroup struct {
+	Queues  []string
+	Workers int
+}
+
+func (g workerQueueGroup) Name() string {
+	if len(g.Queues) == 0 {
+		return "worker"
+	}
+
+	return strings.Join(g.Queues, "+")
+}
+
+// parseWorkerQueues parses the --queue flag. A plain list (async,failed) is consumed by amount workers together,
+// a list with counts (async=3,low_priority=1) starts dedicated workers per queue.
+func parseWorkerQueues(value string, amount int) ([]workerQueueGroup, error) {
+	if value == "" {
+		return []workerQueueGroup{{Workers: max(amount, 1)}}, nil
+	}
+
+	entries := strings.Split(value, ",")
+
+	if !strings.Contains(value, "=") {
+		return []workerQueueGroup{{Queues: entries, Workers: max(amount, 1)}}, nil
+	}
+
+	if amount > 0 {
+		return nil, fmt.Errorf("the worker amount argument cannot be combined with per queue worker counts")
+	}
+
+	groups := make([]workerQueueGroup, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		queue, count, hasCount := strings.Cut(strings.TrimSpace(entry), "=")
+
+		if queue == "" {
+			return nil, fmt.Errorf("invalid queue definition %q", entry)
+		}
+
+		if seen[queue] {
+			return nil, fmt.Errorf("queue %s is defined more than once", queue)
+		}
+
+		seen[queue] = true
+
+		workers := 1
+
+		if hasCount {
+			var err error
+			if workers, err = strconv.Atoi(count); err != nil || workers < 1 {
+				return nil, fmt.Errorf("invalid worker count for queue %s: %q", queue, count)
+			}
+		}
+
+		groups = append(groups, workerQueueGroup{Queues: []string{queue}, Workers: workers})
+	}
+
+	return groups, nil
 }
 
 func cancelOnTermination(ctx context.Context, cancel context.CancelFunc) {
 	logging.FromContext(ctx).Infof("setting up a signal handler")
 	s := make(chan os.Signal, 1)
-	signal.Notify(s, syscall.SIGTERM)
+	signal.Notify(s, supervisor.TerminationSignals...)
 	go func() {
-		logging.FromContext(ctx).Infof("received SIGTERM %v\n", <-s)
-		cancel()
+		select {
+		case sig := <-s:
+			logging.FromContext(ctx).Infof("received %v, waiting for workers to finish", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(s)
 	}()
 }
@@ -0,0 +1,55 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWorkerQueues(t *testing.T) {
+	t.Run("default queues", func(t *testing.T) {
+		groups, err := parseWorkerQueues("", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []workerQueueGroup{{Workers: 1}}, groups)
+	})
+
+	t.Run("plain list uses amount", func(t *testing.T) {
+		groups, err := parseWorkerQueues("async,failed", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []workerQueueGroup{{Queues: []string{"async", "failed"}, Workers: 3}}, groups)
+		assert.Equal(t, "async+failed", groups[0].Name())
+	})
+
+	t.Run("per queue counts", func(t *testing.T) {
+		groups, err := parseWorkerQueues("async=3,low_priority=1,failed", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []workerQueueGroup{
+			{Queues: []string{"async"}, Workers: 3},
+			{Queues: []string{"low_priority"}, Workers: 1},
+			{Queues: []string{"failed"}, Workers: 1},
+		}, groups)
+	})
+
+	t.Run("invalid count", func(t *testing.T) {
+		_, err := parseWorkerQueues("async=abc", 0)
+		assert.Error(t, err)
+
+		_, err = parseWorkerQueues("async=0", 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("duplicate queue", func(t *testing.T) {
+		_, err := parseWorkerQueues("async=2,async=1", 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("amount with counts", func(t *testing.T) {
+		_, err := parseWorkerQueues("async=2", 2)
+		assert.Error(t, err)
+	})
+}
+
+func TestSchedulerArgs(t *testing.T) {
+	assert.Equal(t, []string{"scheduled-task:run"}, schedulerArgs("", "", false))
+	assert.Equal(t, []string{"scheduled-task:run", "--memory-limit=512M", "--time-limit=120", "-vvv"}, schedulerArgs("512M", "120", true))
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -11,6 +12,7 @@ import (
 	"github.com/haokeyingxiao/haoke-cli/cmd/extension"
 	"github.com/haokeyingxiao/haoke-cli/cmd/project"
 	"github.com/haokeyingxiao/haoke-cli/internal/config"
+	"github.com/haokeyingxiao/haoke-cli/internal/system"
 	"github.com/haokeyingxiao/haoke-cli/logging"
 )
 
@@ -37,6 +39,12 @@ func Execute(ctx context.Context) {
 	accountApi.SetUserAgent("haoke-cli/" + version)
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		var exitErr *system.ExitError
+		if errors.As(err, &exitErr) {
+			logging.FromContext(ctx).Error(err)
+			os.Exit(exitErr.Code)
+		}
+
 		logging.FromContext(ctx).Fatalln(err)
 	}
 }
@@ -3,8 +3,10 @@ package extension
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/haokeyingxiao/haoke-cli/internal/changelog"
+	"github.com/haokeyingxiao/haoke-cli/internal/hook"
 
 	"gopkg.in/yaml.v3"
 )
@@ -14,25 +16,28 @@ type ConfigBuild struct {
 	ShopwareVersionConstraint string              `yaml:"shopwareVersionConstraint,omitempty"`
 	Zip                       struct {
 		Composer struct {
-			Enabled          bool     `yaml:"enabled"`
-			BeforeHooks      []string `yaml:"before_hooks,omitempty"`
-			AfterHooks       []string `yaml:"after_hooks,omitempty"`
-			ExcludedPackages []string `yaml:"excluded_packages,omitempty"`
+			Enabled          bool        `yaml:"enabled"`
+			BeforeHooks      []hook.Hook `yaml:"before_hooks,omitempty"`
+			AfterHooks       []hook.Hook `yaml:"after_hooks,omitempty"`
+			ExcludedPackages []string    `yaml:"excluded_packages,omitempty"`
 		} `yaml:"composer"`
 		Assets struct {
-			Enabled                    bool     `yaml:"enabled"`
-			BeforeHooks                []string `yaml:"before_hooks,omitempty"`
-			AfterHooks                 []string `yaml:"after_hooks,omitempty"`
-			EnableESBuildForAdmin      bool     `yaml:"enable_es_build_for_admin"`
-			EnableESBuildForStorefront bool     `yaml:"enable_es_build_for_storefront"`
-			DisableSass                bool     `yaml:"es_build_disable_sass"`
-			NpmStrict                  bool     `yaml:"npm_strict"`
+			Enabled                    bool        `yaml:"enabled"`
+			BeforeHooks                []hook.Hook `yaml:"before_hooks,omitempty"`
+			AfterHooks                 []hook.Hook `yaml:"after_hooks,omitempty"`
+			EnableESBuildForAdmin      bool        `yaml:"enable_es_build_for_admin"`
+			EnableESBuildForStorefront bool        `yaml:"enable_es_build_for_storefront"`
+			DisableSass                bool        `yaml:"es_build_disable_sass"`
+			NpmStrict                  bool        `yaml:"npm_strict"`
 		} `yaml:"assets"`
 		Pack struct {
 			Excludes struct {
 				Paths []string `yaml:"paths,omitempty"`
 			} `yaml:"excludes"`
-			BeforeHooks []string `yaml:"before_hooks,omitempty"`
+			BeforeHooks []hook.Hook `yaml:"before_hooks,omitempty"`
+			AfterHooks  []hook.Hook `yaml:"after_hooks,omitempty"`
+			// SBOM adds a CycloneDX sbom.cdx.json of the bundled composer and npm packages
+			SBOM bool `yaml:"sbom,omitempty"`
 		} `yaml:"pack"`
 	} `yaml:"zip"`
 }
@@ -70,6 +75,46 @@ type ConfigTranslated[T Translatable] struct {
 	Chinese *T `yaml:"zh"`
 }
 
+// ConfigLanguages are the languages which can be translated in the store configuration.
+var ConfigLanguages = []string{"de", "en", "zh"}
+
+// LanguageFromLocale returns the language of a store locale, for example zh for zh_CN.
+func LanguageFromLocale(locale string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(locale, "-", "_"), "_")
+
+	return strings.ToLower(language)
+}
+
+// Get returns the translation for the given language or nil when the language is not configured.
+func (c ConfigTranslated[T]) Get(language string) *T {
+	switch language {
+	case "de":
+		return c.German
+	case "en":
+		return c.English
+	case "zh":
+		return c.Chinese
+	}
+
+	return nil
+}
+
+// Set stores the translation for the given language and reports whether the language is supported.
+func (c *ConfigTranslated[T]) Set(language string, value *T) bool {
+	switch language {
+	case "de":
+		c.German = value
+	case "en":
+		c.English = value
+	case "zh":
+		c.Chinese = value
+	default:
+		return false
+	}
+
+	return true
+}
+
 type ConfigStorePrice struct {
 	Type  string  `yaml:"type"`
 	Money float32 `yaml:"money"`
@@ -99,10 +144,15 @@ type ConfigStoreImagePreview struct {
 	Chinese bool `yaml:"zh"`
 }
 
+type ConfigValidation struct {
+	BeforeHooks []hook.Hook `yaml:"before_hooks,omitempty"`
+}
+
 type Config struct {
-	Store     ConfigStore      `yaml:"store"`
-	Build     ConfigBuild      `yaml:"build"`
-	Changelog changelog.Config `yaml:"changelog"`
+	Store      ConfigStore      `yaml:"store"`
+	Build      ConfigBuild      `yaml:"build"`
+	Changelog  changelog.Config `yaml:"changelog"`
+	Validation ConfigValidation `yaml:"validation"`
 }
 
 func readExtensionConfig(dir string) (*Config, error) {
@@ -133,36 +183,25 @@ func readExtensionConfig(dir string) (*Config, error) {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	err = validateExtensionConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf(errorFormat, err)
-	}
-
 	return config, nil
 }
 
-func validateExtensionConfig(config *Config) error {
-	if config.Store.Tags.English != nil && len(*config.Store.Tags.English) > 5 {
-		return fmt.Errorf("store.info.tags.en can contain maximal 5 items")
-	}
-
-	if config.Store.Tags.German != nil && len(*config.Store.Tags.German) > 5 {
-		return fmt.Errorf("store.info.tags.de can contain maximal 5 items")
-	}
-	if config.Store.Tags.Chinese != nil && len(*config.Store.Tags.Chinese) > 5 {
-		return fmt.Errorf("store.info.tags.zh can contain maximal 5 items")
-	}
+// ValidateStoreLimits returns a message for every language exceeding the amount of tags or videos accepted by the store.
+// These are reported by the validation and the store preview and only rejected when the store info is pushed.
+func ValidateStoreLimits(config *Config) []string {
+	var problems []string
 
-	if config.Store.Videos.English != nil && len(*config.Store.Videos.English) > 2 {
-		return fmt.Errorf("store.info.videos.en can contain maximal 2 items")
+	for _, language := range ConfigLanguages {
+		if tags := config.Store.Tags.Get(language); tags != nil && len(*tags) > StoreMaxTags {
+			problems = append(problems, fmt.Sprintf("store.info.tags.%s can contain maximal %d items", language, StoreMaxTags))
+		}
 	}
 
-	if config.Store.Videos.German != nil && len(*config.Store.Videos.German) > 2 {
-		return fmt.Errorf("store.info.videos.de can contain maximal 2 items")
+	for _, language := range ConfigLanguages {
+		if videos := config.Store.Videos.Get(language); videos != nil && len(*videos) > StoreMaxVideos {
+			problems = append(problems, fmt.Sprintf("store.info.videos.%s can contain maximal %d items", language, StoreMaxVideos))
+		}
 	}
 
-	if config.Store.Videos.Chinese != nil && len(*config.Store.Videos.Chinese) > 2 {
-		return fmt.Errorf("store.info.videos.zh can contain maximal 2 items")
-	}
-	return nil
+	return problems
 }
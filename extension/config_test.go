@@ -0,0 +1,61 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/hook"
+)
+
+func TestLanguageFromLocale(t *testing.T) {
+	assert.Equal(t, "de", LanguageFromLocale("de_DE"))
+	assert.Equal(t, "en", LanguageFromLocale("en-GB"))
+	assert.Equal(t, "zh", LanguageFromLocale("zh_CN"))
+	assert.Equal(t, "zh", LanguageFromLocale("ZH"))
+}
+
+func TestConfigTranslatedGetAndSet(t *testing.T) {
+	var config ConfigTranslated[string]
+
+	german := "Beschreibung"
+	chinese := "描述"
+
+	assert.True(t, config.Set("de", &german))
+	assert.True(t, config.Set("zh", &chinese))
+	assert.False(t, config.Set("fr", &german))
+
+	assert.Equal(t, &german, config.Get("de"))
+	assert.Equal(t, &chinese, config.Get("zh"))
+	assert.Nil(t, config.Get("en"))
+	assert.Nil(t, config.Get("fr"))
+}
+
+func TestReadExtensionConfigHooks(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".haoke-extension.yml"), []byte(`
+build:
+  zip:
+    composer:
+      before_hooks:
+        - echo "Before"
+    pack:
+      after_hooks:
+        - command: gpg
+          args: [--detach-sign, $ZIP_FILE]
+          only_on: release
+validation:
+  before_hooks:
+    - composer dump-autoload
+`), os.ModePerm))
+
+	config, err := readExtensionConfig(dir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []hook.Hook{{Command: `echo "Before"`}}, config.Build.Zip.Composer.BeforeHooks)
+	assert.Equal(t, []hook.Hook{{Command: "gpg", Args: []string{"--detach-sign", "$ZIP_FILE"}, OnlyOn: hook.OnlyOnRelease}}, config.Build.Zip.Pack.AfterHooks)
+	assert.Equal(t, []hook.Hook{{Command: "composer dump-autoload"}}, config.Validation.BeforeHooks)
+}
@@ -0,0 +1,156 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+type dependencyComposerJson struct {
+	Name    string            `json:"name"`
+	Require map[string]string `json:"require"`
+}
+
+// FindExtensionDependencies returns for each extension of the project the names of the other project extensions it requires in its composer.json.
+// App manifests have no way to declare requirements, so apps without a composer.json never have dependencies.
+func FindExtensionDependencies(ctx context.Context, project string) map[string][]string {
+	extensions := FindExtensionsFromProject(ctx, project)
+
+	packageToExtension := make(map[string]string)
+	requires := make(map[string]map[string]string)
+
+	for _, ext := range extensions {
+		name, err := ext.GetName()
+		if err != nil {
+			continue
+		}
+
+		content, err := os.ReadFile(path.Join(ext.GetPath(), "composer.json"))
+		if err != nil {
+			continue
+		}
+
+		var composer dependencyComposerJson
+		if err := json.Unmarshal(content, &composer); err != nil {
+			continue
+		}
+
+		if composer.Name != "" {
+			packageToExtension[strings.ToLower(composer.Name)] = name
+		}
+
+		requires[name] = composer.Require
+	}
+
+	dependencies := make(map[string][]string)
+
+	for name, require := range requires {
+		for packageName := range require {
+			dependency, ok := packageToExtension[strings.ToLower(packageName)]
+			if !ok || dependency == name {
+				continue
+			}
+
+			dependencies[name] = append(dependencies[name], dependency)
+		}
+
+		sort.Strings(dependencies[name])
+	}
+
+	return dependencies
+}
+
+// SortByDependencies orders the names so that every extension comes after the extensions it depends on.
+// Dependencies outside of names are ignored, otherwise the given order is kept.
+func SortByDependencies(names []string, dependencies map[string][]string) ([]string, error) {
+	inSet := make(map[string]bool, len(names))
+	for _, name := range names {
+		inSet[name] = true
+	}
+
+	sorted := make([]string, 0, len(names))
+	state := make(map[string]int, len(names))
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	var visit func(name string, stack []string) error
+	visit = func(name string, stack []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("circular dependency between extensions: %s -> %s", strings.Join(stack, " -> "), name)
+		}
+
+		state[name] = visiting
+
+		for _, dependency := range dependencies[name] {
+			if !inSet[dependency] {
+				continue
+			}
+
+			if err := visit(dependency, append(stack, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+		sorted = append(sorted, name)
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// FindDependents returns all extensions which depend directly or transitively on one of the given names.
+func FindDependents(names []string, dependencies map[string][]string) []string {
+	dependents := make(map[string]bool)
+	queue := append([]string{}, names...)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for name, deps := range dependencies {
+			if dependents[name] {
+				continue
+			}
+
+			for _, dep := range deps {
+				if dep == current {
+					dependents[name] = true
+					queue = append(queue, name)
+
+					break
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		delete(dependents, name)
+	}
+
+	result := make([]string, 0, len(dependents))
+	for name := range dependents {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
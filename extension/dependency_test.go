@@ -0,0 +1,65 @@
+package extension
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortByDependencies(t *testing.T) {
+	dependencies := map[string][]string{
+		"Child":      {"Parent"},
+		"GrandChild": {"Child", "Unrelated"},
+	}
+
+	sorted, err := SortByDependencies([]string{"GrandChild", "Child", "Parent", "Other"}, dependencies)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Parent", "Child", "GrandChild", "Other"}, sorted)
+
+	sorted, err = SortByDependencies([]string{"GrandChild", "Parent"}, dependencies)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GrandChild", "Parent"}, sorted)
+}
+
+func TestSortByDependenciesCircular(t *testing.T) {
+	_, err := SortByDependencies([]string{"A", "B"}, map[string][]string{
+		"A": {"B"},
+		"B": {"A"},
+	})
+
+	assert.ErrorContains(t, err, "circular dependency")
+}
+
+func TestFindDependents(t *testing.T) {
+	dependencies := map[string][]string{
+		"Child":      {"Parent"},
+		"GrandChild": {"Child"},
+		"Other":      {"Unrelated"},
+	}
+
+	assert.Equal(t, []string{"Child", "GrandChild"}, FindDependents([]string{"Parent"}, dependencies))
+	assert.Equal(t, []string{"GrandChild"}, FindDependents([]string{"Child"}, dependencies))
+	assert.Empty(t, FindDependents([]string{"GrandChild"}, dependencies))
+}
+
+func TestFindExtensionDependencies(t *testing.T) {
+	project := t.TempDir()
+
+	plugins := map[string]string{
+		"BasePlugin":  `{"name": "vendor/base-plugin", "type": "shopware-platform-plugin", "version": "1.0.0", "require": {"haokeyingxiao/core": "~6.5.0"}, "extra": {"shopware-plugin-class": "Vendor\\BasePlugin"}}`,
+		"AddonPlugin": `{"name": "vendor/addon-plugin", "type": "shopware-platform-plugin", "version": "1.0.0", "require": {"haokeyingxiao/core": "~6.5.0", "Vendor/Base-Plugin": "*"}, "extra": {"shopware-plugin-class": "Vendor\\AddonPlugin"}}`,
+	}
+
+	for name, composerJson := range plugins {
+		pluginDir := path.Join(project, "custom", "plugins", name)
+		assert.NoError(t, os.MkdirAll(pluginDir, os.ModePerm))
+		assert.NoError(t, os.WriteFile(path.Join(pluginDir, "composer.json"), []byte(composerJson), os.ModePerm))
+	}
+
+	dependencies := FindExtensionDependencies(context.Background(), project)
+
+	assert.Equal(t, map[string][]string{"AddonPlugin": {"BasePlugin"}}, dependencies)
+}
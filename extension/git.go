@@ -5,7 +5,9 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func gitTagOrBranchOfFolder(source string) (string, error) {
@@ -34,12 +36,27 @@ func gitTagOrBranchOfFolder(source string) (string, error) {
 }
 
 func GitCopyFolder(source, target, commitHash string) (string, error) {
+	zipReader, commitHash, err := gitArchive(source, commitHash)
+	if err != nil {
+		return "", fmt.Errorf("GitCopyFolder: %v", err)
+	}
+
+	err = Unzip(zipReader, target)
+	if err != nil {
+		return "", fmt.Errorf("GitCopyFolder: cannot unzip the zip archive: %v", err)
+	}
+
+	return commitHash, err
+}
+
+// gitArchive creates the archive of the commit in memory, without commit the latest tag or the branch is used.
+func gitArchive(source, commitHash string) (*zip.Reader, string, error) {
 	var err error
 	if commitHash == "" {
 		commitHash, err = gitTagOrBranchOfFolder(source)
 
 		if err != nil {
-			return "", fmt.Errorf("GitCopyFolder: cannot find checkout tag or branch: %v", err)
+			return nil, "", fmt.Errorf("cannot find checkout tag or branch: %v", err)
 		}
 	}
 
@@ -47,18 +64,29 @@ func GitCopyFolder(source, target, commitHash string) (string, error) {
 
 	stdout, err := archiveCmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("GitCopyFolder: cannot archive %s:  %v", commitHash, err)
+		return nil, "", fmt.Errorf("cannot archive %s:  %v", commitHash, err)
 	}
 
 	zipReader, err := zip.NewReader(bytes.NewReader(stdout), int64(len(stdout)))
 	if err != nil {
-		return "", fmt.Errorf("GitCopyFolder: cannot open the zip file produced by git archive: %v", err)
+		return nil, "", fmt.Errorf("cannot open the zip file produced by git archive: %v", err)
 	}
 
-	err = Unzip(zipReader, target)
+	return zipReader, commitHash, nil
+}
+
+func gitCommitTime(source, reference string) (time.Time, error) {
+	logCmd := exec.Command("git", "-C", source, "log", "-1", "--format=%ct", reference)
+
+	stdout, err := logCmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("GitCopyFolder: cannot unzip the zip archive: %v", err)
+		return time.Time{}, fmt.Errorf("gitCommitTime: %v", err)
 	}
 
-	return commitHash, err
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(stdout)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("gitCommitTime: %v", err)
+	}
+
+	return time.Unix(seconds, 0), nil
 }
@@ -0,0 +1,53 @@
+package extension
+
+import (
+	"context"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/hook"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+// HookEnv returns the environment variables passed to the hooks of the extension.
+func HookEnv(ctx context.Context, ext Extension, extDir, zipFile string) map[string]string {
+	env := map[string]string{
+		"EXTENSION_DIR":          extDir,
+		"ORIGINAL_EXTENSION_DIR": ext.GetPath(),
+		"EXTENSION_TYPE":         ext.GetType(),
+		"ZIP_FILE":               zipFile,
+	}
+
+	if name, err := ext.GetName(); err == nil {
+		env["EXTENSION_NAME"] = name
+	}
+
+	if extVersion, err := ext.GetVersion(); err == nil {
+		env["EXTENSION_VERSION"] = extVersion.String()
+	}
+
+	if constraint, err := ext.GetShopwareVersionConstraint(); err == nil {
+		env["SHOPWARE_VERSION_CONSTRAINT"] = constraint.String()
+	}
+
+	if minVersion, _, err := LookupMinShopwareVersion(ctx, ext); err == nil {
+		env["SHOPWARE_VERSION"] = minVersion
+	} else {
+		logging.FromContext(ctx).Debugf("cannot determine the target core version for hooks: %v", err)
+	}
+
+	return env
+}
+
+// RunBeforeValidateHooks executes the before_validate hooks of the extension in its folder.
+func RunBeforeValidateHooks(ctx context.Context, ext Extension, release bool) error {
+	cfg := ext.GetExtensionConfig()
+	if cfg == nil || len(cfg.Validation.BeforeHooks) == 0 {
+		return nil
+	}
+
+	return hook.Run(ctx, cfg.Validation.BeforeHooks, hook.Context{
+		Stage:   hook.StageBeforeValidate,
+		Dir:     ext.GetPath(),
+		Release: release,
+		Env:     HookEnv(ctx, ext, ext.GetPath(), ""),
+	})
+}
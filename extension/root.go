@@ -78,6 +78,20 @@ type extensionTranslated struct {
 	Chinese string `json:"chinese"`
 }
 
+// Get returns the translation for the given language (de, en, zh).
+func (t extensionTranslated) Get(language string) string {
+	switch language {
+	case "de":
+		return t.German
+	case "en":
+		return t.English
+	case "zh":
+		return t.Chinese
+	}
+
+	return ""
+}
+
 type extensionMetadata struct {
 	Name        string
 	Label       extensionTranslated
@@ -0,0 +1,235 @@
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SBOMFileName is the CycloneDX document embedded into the zip.
+const SBOMFileName = "sbom.cdx.json"
+
+type sbomDocument struct {
+	BOMFormat   string          `json:"bomFormat"`
+	SpecVersion string          `json:"specVersion"`
+	Version     int             `json:"version"`
+	Metadata    sbomMetadata    `json:"metadata"`
+	Components  []sbomComponent `json:"components"`
+}
+
+type sbomMetadata struct {
+	Timestamp string        `json:"timestamp"`
+	Component sbomComponent `json:"component"`
+}
+
+type sbomComponent struct {
+	Type     string        `json:"type"`
+	Name     string        `json:"name"`
+	Version  string        `json:"version,omitempty"`
+	Purl     string        `json:"purl,omitempty"`
+	Licenses []sbomLicense `json:"licenses,omitempty"`
+}
+
+type sbomLicense struct {
+	License struct {
+		ID   string `json:"id,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"license"`
+}
+
+type composerInstalledPackage struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	License []string `json:"license"`
+}
+
+type npmLockPackage struct {
+	Version string          `json:"version"`
+	License json.RawMessage `json:"license"`
+	Dev     bool            `json:"dev"`
+}
+
+// GenerateSBOM lists the composer packages of the vendor folder and the npm packages bundled into the assets as CycloneDX.
+func GenerateSBOM(ext Extension, folder string, timestamp time.Time) ([]byte, error) {
+	name, err := ext.GetName()
+	if err != nil {
+		return nil, err
+	}
+
+	extVersion, err := ext.GetVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	composerPackages, err := readComposerInstalledPackages(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	npmPackages, err := readNpmLockPackages(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	components := append(composerPackages, npmPackages...)
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Purl < components[j].Purl
+	})
+
+	document := sbomDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: sbomMetadata{
+			Timestamp: timestamp.UTC().Format(time.RFC3339),
+			Component: sbomComponent{Type: "application", Name: name, Version: extVersion.String()},
+		},
+		Components: components,
+	}
+
+	return json.MarshalIndent(document, "", "  ")
+}
+
+func readComposerInstalledPackages(folder string) ([]sbomComponent, error) {
+	content, err := os.ReadFile(filepath.Join(folder, "vendor", "composer", "installed.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// composer 2 wraps the packages, composer 1 writes only the list
+	var installed struct {
+		Packages []composerInstalledPackage `json:"packages"`
+	}
+
+	if err := json.Unmarshal(content, &installed); err != nil {
+		if err := json.Unmarshal(content, &installed.Packages); err != nil {
+			return nil, fmt.Errorf("cannot read vendor/composer/installed.json: %w", err)
+		}
+	}
+
+	components := make([]sbomComponent, 0, len(installed.Packages))
+
+	for _, pkg := range installed.Packages {
+		components = append(components, sbomComponent{
+			Type:     "library",
+			Name:     pkg.Name,
+			Version:  pkg.Version,
+			Purl:     fmt.Sprintf("pkg:composer/%s@%s", pkg.Name, pkg.Version),
+			Licenses: sbomLicenses(pkg.License),
+		})
+	}
+
+	return components, nil
+}
+
+// readNpmLockPackages reads the production packages of all package-lock.json, the node_modules are removed before zipping.
+func readNpmLockPackages(folder string) ([]sbomComponent, error) {
+	components := make([]sbomComponent, 0)
+	seen := map[string]bool{}
+
+	err := filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && (d.Name() == "node_modules" || d.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+
+		if d.IsDir() || d.Name() != "package-lock.json" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var lock struct {
+			Packages map[string]npmLockPackage `json:"packages"`
+		}
+
+		if err := json.Unmarshal(content, &lock); err != nil {
+			return fmt.Errorf("cannot read %s: %w", path, err)
+		}
+
+		for key, pkg := range lock.Packages {
+			index := strings.LastIndex(key, "node_modules/")
+			if index == -1 || pkg.Dev {
+				continue
+			}
+
+			name := key[index+len("node_modules/"):]
+			purl := fmt.Sprintf("pkg:npm/%s@%s", strings.Replace(name, "@", "%40", 1), pkg.Version)
+
+			if seen[purl] {
+				continue
+			}
+
+			seen[purl] = true
+
+			components = append(components, sbomComponent{
+				Type:     "library",
+				Name:     name,
+				Version:  pkg.Version,
+				Purl:     purl,
+				Licenses: sbomLicenses(npmLicenses(pkg.License)),
+			})
+		}
+
+		return nil
+	})
+
+	return components, err
+}
+
+// npmLicenses supports the license as string and the deprecated object form.
+func npmLicenses(raw json.RawMessage) []string {
+	var license string
+	if err := json.Unmarshal(raw, &license); err == nil {
+		return []string{license}
+	}
+
+	var licenseObject struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(raw, &licenseObject); err == nil && licenseObject.Type != "" {
+		return []string{licenseObject.Type}
+	}
+
+	return nil
+}
+
+func sbomLicenses(licenses []string) []sbomLicense {
+	result := make([]sbomLicense, 0, len(licenses))
+
+	for _, license := range licenses {
+		if license == "" {
+			continue
+		}
+
+		var entry sbomLicense
+
+		// proprietary or custom licenses are no valid SPDX ids
+		if strings.ContainsAny(license, " ()") || strings.EqualFold(license, "proprietary") {
+			entry.License.Name = license
+		} else {
+			entry.License.ID = license
+		}
+
+		result = append(result, entry)
+	}
+
+	return result
+}
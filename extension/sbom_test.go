@@ -0,0 +1,51 @@
+package extension
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateSBOM(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "vendor", "composer"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "vendor", "composer", "installed.json"), []byte(`{"packages":[{"name":"symfony/yaml","version":"v6.4.0","license":["MIT"]}]}`), os.ModePerm))
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "src", "Resources", "app", "administration"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "src", "Resources", "app", "administration", "package-lock.json"), []byte(`{"packages":{
+		"": {"name": "admin"},
+		"node_modules/@vue/compat": {"version": "3.4.0", "license": "MIT"},
+		"node_modules/jest": {"version": "29.0.0", "dev": true}
+	}}`), os.ModePerm))
+
+	content, err := GenerateSBOM(getTestPlugin(dir), dir, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+
+	var document sbomDocument
+	assert.NoError(t, json.Unmarshal(content, &document))
+
+	assert.Equal(t, "CycloneDX", document.BOMFormat)
+	assert.Equal(t, "2024-03-01T00:00:00Z", document.Metadata.Timestamp)
+	assert.Equal(t, "1.0.0", document.Metadata.Component.Version)
+	assert.Len(t, document.Components, 2)
+	assert.Equal(t, "pkg:composer/symfony/yaml@v6.4.0", document.Components[0].Purl)
+	assert.Equal(t, "MIT", document.Components[0].Licenses[0].License.ID)
+	assert.Equal(t, "pkg:npm/%40vue/compat@3.4.0", document.Components[1].Purl)
+}
+
+func TestReadComposerInstalledPackagesOfComposer1(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "vendor", "composer"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "vendor", "composer", "installed.json"), []byte(`[{"name":"a/b","version":"1.0.0","license":["proprietary"]}]`), os.ModePerm))
+
+	components, err := readComposerInstalledPackages(dir)
+	assert.NoError(t, err)
+	assert.Len(t, components, 1)
+	assert.Equal(t, "proprietary", components[0].Licenses[0].License.Name)
+}
@@ -0,0 +1,289 @@
+package extension
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+const (
+	// StoreMaxTags is the maximum amount of tags per language accepted by the store.
+	StoreMaxTags = 5
+	// StoreMaxVideos is the maximum amount of videos per language accepted by the store.
+	StoreMaxVideos = 2
+	// StoreImageMaxSize is the maximum file size of a store image in bytes.
+	StoreImageMaxSize = 5 * 1024 * 1024
+	// StoreImageMaxWidth and StoreImageMaxHeight are the maximum dimensions of a store image.
+	StoreImageMaxWidth  = 1920
+	StoreImageMaxHeight = 1080
+)
+
+var storeImageNameRegExp = regexp.MustCompile(`^(\d+)([_-][a-zA-Z0-9-_]+)?$`)
+
+type StorePreview struct {
+	Language           string
+	Name               string
+	ShortDescription   string
+	Description        template.HTML
+	InstallationManual template.HTML
+	Tags               []string
+	Videos             []string
+	Highlights         []string
+	Features           []string
+	Faq                []StorePreviewFaq
+	Images             []StorePreviewImage
+	Problems           []string
+}
+
+type StorePreviewFaq struct {
+	Question string
+	Answer   template.HTML
+}
+
+type StorePreviewImage struct {
+	File     string
+	Priority int
+	Preview  bool
+}
+
+// BuildStorePreviews renders the store information of the extension for every configured language and collects content the store would reject.
+func BuildStorePreviews(ext Extension) ([]StorePreview, error) {
+	cfg := ext.GetExtensionConfig()
+	metadata := ext.GetMetaData()
+	policy := bluemonday.UGCPolicy()
+
+	previews := make([]StorePreview, 0, len(ConfigLanguages))
+
+	for _, language := range ConfigLanguages {
+		preview := StorePreview{
+			Language:         language,
+			Name:             metadata.Label.Get(language),
+			ShortDescription: metadata.Description.Get(language),
+		}
+
+		if preview.Name == "" {
+			preview.Problems = append(preview.Problems, "label is missing")
+		}
+
+		if preview.ShortDescription == "" {
+			preview.Problems = append(preview.Problems, "short description is missing")
+		}
+
+		description, err := readStoreHTML(cfg.Store.Description.Get(language), ext.GetPath(), policy)
+		if err != nil {
+			return nil, err
+		}
+
+		if description == "" {
+			preview.Problems = append(preview.Problems, "description is missing")
+		}
+
+		installationManual, err := readStoreHTML(cfg.Store.InstallationManual.Get(language), ext.GetPath(), policy)
+		if err != nil {
+			return nil, err
+		}
+
+		if installationManual == "" {
+			preview.Problems = append(preview.Problems, "installation manual is missing")
+		}
+
+		preview.Description = description
+		preview.InstallationManual = installationManual
+		preview.Tags = translatedList(cfg.Store.Tags.Get(language))
+		preview.Videos = translatedList(cfg.Store.Videos.Get(language))
+		preview.Highlights = translatedList(cfg.Store.Highlights.Get(language))
+		preview.Features = translatedList(cfg.Store.Features.Get(language))
+
+		if len(preview.Tags) > StoreMaxTags {
+			preview.Problems = append(preview.Problems, fmt.Sprintf("%d tags are configured, the store accepts maximal %d", len(preview.Tags), StoreMaxTags))
+		}
+
+		if len(preview.Videos) > StoreMaxVideos {
+			preview.Problems = append(preview.Problems, fmt.Sprintf("%d videos are configured, the store accepts maximal %d", len(preview.Videos), StoreMaxVideos))
+		}
+
+		if faqs := cfg.Store.Faq.Get(language); faqs != nil {
+			for _, faq := range *faqs {
+				preview.Faq = append(preview.Faq, StorePreviewFaq{
+					Question: faq.Question,
+					Answer:   template.HTML(policy.Sanitize(faq.Answer)), //nolint:gosec
+				})
+			}
+		}
+
+		images, err := storeImagesOfLanguage(cfg, ext.GetPath(), language)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, image := range images {
+			preview.Problems = append(preview.Problems, checkStoreImage(image.File)...)
+		}
+
+		preview.Images = images
+
+		previews = append(previews, preview)
+	}
+
+	return previews, nil
+}
+
+// ReadInlineablePath returns the value itself or the content of the referenced file when it starts with file:.
+// Markdown files are converted to HTML.
+func ReadInlineablePath(path, extensionDir string) (string, error) {
+	if !strings.HasPrefix(path, "file:") {
+		return path, nil
+	}
+
+	filePath := fmt.Sprintf("%s/%s", extensionDir, strings.TrimPrefix(path, "file:"))
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("error reading file at path %s with error: %v", filePath, err)
+	}
+
+	if filepath.Ext(filePath) != ".md" {
+		return string(content), nil
+	}
+
+	md := GetConfiguredGoldMark()
+
+	var buf bytes.Buffer
+	err = md.Convert(content, &buf)
+
+	if err != nil {
+		return "", fmt.Errorf("cannot convert file at path %s from markdown to html with error: %v", filePath, err)
+	}
+
+	return buf.String(), nil
+}
+
+func readStoreHTML(value *string, extensionDir string, policy *bluemonday.Policy) (template.HTML, error) {
+	if value == nil {
+		return "", nil
+	}
+
+	content, err := ReadInlineablePath(*value, extensionDir)
+	if err != nil {
+		return "", err
+	}
+
+	return template.HTML(policy.Sanitize(content)), nil //nolint:gosec
+}
+
+func translatedList(list *[]string) []string {
+	if list == nil {
+		return nil
+	}
+
+	return *list
+}
+
+func storeImagesOfLanguage(cfg *Config, extensionDir, language string) ([]StorePreviewImage, error) {
+	images := make([]StorePreviewImage, 0)
+
+	if cfg.Store.Images != nil {
+		for _, configImage := range *cfg.Store.Images {
+			if !storeImageFlag(configImage.Activate.German, configImage.Activate.English, configImage.Activate.Chinese, language) {
+				continue
+			}
+
+			images = append(images, StorePreviewImage{
+				File:     filepath.Join(extensionDir, configImage.File),
+				Priority: configImage.Priority,
+				Preview:  storeImageFlag(configImage.Preview.German, configImage.Preview.English, configImage.Preview.Chinese, language),
+			})
+		}
+	} else if cfg.Store.ImageDirectory != nil {
+		directory := filepath.Join(extensionDir, *cfg.Store.ImageDirectory, language)
+
+		entries, err := os.ReadDir(directory)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			matches := storeImageNameRegExp.FindStringSubmatch(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+			if matches == nil {
+				continue
+			}
+
+			priority, _ := strconv.Atoi(matches[1])
+
+			images = append(images, StorePreviewImage{
+				File:     filepath.Join(directory, entry.Name()),
+				Priority: priority,
+			})
+		}
+	}
+
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].Priority < images[j].Priority
+	})
+
+	return images, nil
+}
+
+func storeImageFlag(german, english, chinese bool, language string) bool {
+	switch language {
+	case "de":
+		return german
+	case "en":
+		return english
+	case "zh":
+		return chinese
+	}
+
+	return false
+}
+
+func checkStoreImage(file string) []string {
+	name := filepath.Base(file)
+
+	stat, err := os.Stat(file)
+	if err != nil {
+		return []string{fmt.Sprintf("image %s cannot be read", name)}
+	}
+
+	problems := make([]string, 0)
+
+	if stat.Size() > StoreImageMaxSize {
+		problems = append(problems, fmt.Sprintf("image %s is %d bytes, the store accepts maximal %d bytes", name, stat.Size(), StoreImageMaxSize))
+	}
+
+	fileHandle, err := os.Open(file)
+	if err != nil {
+		return append(problems, fmt.Sprintf("image %s cannot be read", name))
+	}
+
+	defer func() {
+		_ = fileHandle.Close()
+	}()
+
+	imageConfig, _, err := image.DecodeConfig(fileHandle)
+	if err != nil {
+		return append(problems, fmt.Sprintf("image %s is not a supported image", name))
+	}
+
+	if imageConfig.Width > StoreImageMaxWidth || imageConfig.Height > StoreImageMaxHeight {
+		problems = append(problems, fmt.Sprintf("image %s is %dx%d, the store accepts maximal %dx%d", name, imageConfig.Width, imageConfig.Height, StoreImageMaxWidth, StoreImageMaxHeight))
+	}
+
+	return problems
+}
@@ -0,0 +1,106 @@
+package extension
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorePreviewRendersAndSanitizes(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "src/Resources/store"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "src/Resources/store/description.en.md"), []byte("# Title\n\n**bold** <script>alert(1)</script>"), os.ModePerm))
+
+	description := "file:src/Resources/store/description.en.md"
+	manual := "<p onclick=\"alert(1)\">Install</p>"
+	tags := []string{"a", "b", "c", "d", "e", "f"}
+
+	plugin := getTestPlugin(dir)
+	plugin.config = &Config{}
+	plugin.config.Store.Description.English = &description
+	plugin.config.Store.InstallationManual.English = &manual
+	plugin.config.Store.Tags.English = &tags
+
+	previews, err := BuildStorePreviews(plugin)
+	assert.NoError(t, err)
+	assert.Len(t, previews, len(ConfigLanguages))
+
+	english := previews[1]
+	assert.Equal(t, "en", english.Language)
+	assert.Equal(t, "Frosh Tools", english.Name)
+	assert.Contains(t, string(english.Description), "<strong>bold</strong>")
+	assert.NotContains(t, string(english.Description), "<script>")
+	assert.Equal(t, "<p>Install</p>", string(english.InstallationManual))
+	assert.Contains(t, english.Problems, "6 tags are configured, the store accepts maximal 5")
+
+	german := previews[0]
+	assert.Contains(t, german.Problems, "description is missing")
+	assert.Contains(t, german.Problems, "installation manual is missing")
+}
+
+func TestStorePreviewImagesOfDirectory(t *testing.T) {
+	dir := t.TempDir()
+	imageDir := filepath.Join(dir, "images", "en")
+
+	assert.NoError(t, os.MkdirAll(imageDir, os.ModePerm))
+
+	writeTestImage(t, filepath.Join(imageDir, "2.png"), 100, 100)
+	writeTestImage(t, filepath.Join(imageDir, "1-big.png"), StoreImageMaxWidth+1, 10)
+	assert.NoError(t, os.WriteFile(filepath.Join(imageDir, "invalid.png"), []byte("test"), os.ModePerm))
+
+	imageDirectory := "images"
+	cfg := &Config{}
+	cfg.Store.ImageDirectory = &imageDirectory
+
+	images, err := storeImagesOfLanguage(cfg, dir, "en")
+	assert.NoError(t, err)
+	assert.Len(t, images, 2)
+	assert.Equal(t, 1, images[0].Priority)
+	assert.Equal(t, 2, images[1].Priority)
+
+	assert.Len(t, checkStoreImage(images[0].File), 1)
+	assert.Len(t, checkStoreImage(images[1].File), 0)
+
+	images, err = storeImagesOfLanguage(cfg, dir, "zh")
+	assert.NoError(t, err)
+	assert.Len(t, images, 0)
+}
+
+func writeTestImage(t *testing.T, file string, width, height int) {
+	t.Helper()
+
+	f, err := os.Create(file)
+	assert.NoError(t, err)
+
+	defer f.Close()
+
+	assert.NoError(t, png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height))))
+}
+
+func TestStorePreviewReportsTooManyTagsFromConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	composer := `{"name": "frosh/frosh-tools", "type": "shopware-platform-plugin", "version": "1.0.0", "require": {"haokeyingxiao/core": "~6.5.0"}, "extra": {"shopware-plugin-class": "FroshTools\\FroshTools", "label": {"en-GB": "Frosh Tools"}}}`
+	config := "store:\n  tags:\n    en: [a, b, c, d, e, f]\n  videos:\n    en: [a, b, c]\n"
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(composer), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".haoke-extension.yml"), []byte(config), os.ModePerm))
+
+	plugin, err := newPlatformPlugin(dir)
+	assert.NoError(t, err)
+
+	previews, err := BuildStorePreviews(plugin)
+	assert.NoError(t, err)
+
+	english := previews[1]
+	assert.Equal(t, "en", english.Language)
+	assert.Contains(t, english.Problems, "6 tags are configured, the store accepts maximal 5")
+	assert.Contains(t, english.Problems, "3 videos are configured, the store accepts maximal 2")
+
+	assert.Equal(t, []string{"store.info.tags.en can contain maximal 5 items", "store.info.videos.en can contain maximal 2 items"}, ValidateStoreLimits(plugin.GetExtensionConfig()))
+}
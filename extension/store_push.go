@@ -0,0 +1,446 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	accountApi "github.com/haokeyingxiao/haoke-cli/account-api"
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+// PushStoreInfo updates the store page of the extension and reports whether something was sent.
+// With onlyChanged the texts are only sent when they differ from the store and the icon and images only
+// when their content or priority differs from the store page.
+func PushStoreInfo(ctx context.Context, p *accountApi.ProducerEndpoint, zipExt Extension, onlyChanged bool) (bool, error) {
+	zipName, err := zipExt.GetName()
+	if err != nil {
+		return false, fmt.Errorf("cannot get name: %w", err)
+	}
+
+	storeExt, err := p.GetExtensionByName(ctx, zipName)
+	if err != nil {
+		return false, fmt.Errorf("cannot get store extension: %w", err)
+	}
+
+	before, err := json.Marshal(storeExt)
+	if err != nil {
+		return false, err
+	}
+
+	metadata := zipExt.GetMetaData()
+
+	for _, info := range storeExt.Infos {
+		language := LanguageFromLocale(info.Locale.Name)
+
+		if label := metadata.Label.Get(language); label != "" {
+			info.Name = label
+		}
+
+		if description := metadata.Description.Get(language); description != "" {
+			info.ShortDescription = description
+		}
+	}
+
+	info, err := p.GetExtensionGeneralInfo(ctx)
+	if err != nil {
+		return false, fmt.Errorf("cannot get general info: %w", err)
+	}
+
+	extCfg := zipExt.GetExtensionConfig()
+
+	if extCfg != nil {
+		if err := updateStoreInfo(storeExt, zipExt, extCfg, info); err != nil {
+			return false, fmt.Errorf("cannot update store information: %w", err)
+		}
+	}
+
+	textChanged := true
+	mediaChanged := extCfg != nil
+
+	if onlyChanged {
+		after, err := json.Marshal(storeExt)
+		if err != nil {
+			return false, err
+		}
+
+		textChanged = !bytes.Equal(before, after)
+
+		if extCfg != nil {
+			if mediaChanged, err = storeMediaChanged(ctx, p, storeExt, zipExt, extCfg); err != nil {
+				return false, err
+			}
+		}
+
+		if !textChanged && !mediaChanged {
+			return false, nil
+		}
+	}
+
+	if mediaChanged {
+		if err := pushStoreMedia(ctx, p, storeExt.Id, zipExt, extCfg); err != nil {
+			return false, err
+		}
+	}
+
+	if textChanged {
+		if err := p.UpdateExtension(ctx, storeExt); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// CompatibleSoftwareVersions returns the store software versions matching the constraint of the extension.
+func CompatibleSoftwareVersions(ctx context.Context, p *accountApi.ProducerEndpoint, ext Extension) ([]string, error) {
+	availableVersions, err := p.GetSoftwareVersions(ctx, ext.GetType())
+	if err != nil {
+		return nil, err
+	}
+
+	constraint, err := ext.GetShopwareVersionConstraint()
+	if err != nil {
+		return nil, err
+	}
+
+	return availableVersions.FilterOnVersionStringList(constraint), nil
+}
+
+// storeMediaChanged compares the configured icon and images with the store page by content and image priority.
+func storeMediaChanged(ctx context.Context, p *accountApi.ProducerEndpoint, storeExt *accountApi.Extension, zipExt Extension, extCfg *Config) (bool, error) {
+	if extCfg.Store.Icon != nil {
+		local, err := os.ReadFile(filepath.Join(zipExt.GetPath(), *extCfg.Store.Icon))
+		if err != nil {
+			return false, fmt.Errorf("cannot read extension icon: %w", err)
+		}
+
+		if storeExt.IconURL == "" {
+			return true, nil
+		}
+
+		remote, err := p.DownloadStoreMedia(ctx, storeExt.IconURL)
+		if err != nil {
+			return false, fmt.Errorf("cannot download store icon: %w", err)
+		}
+
+		if !bytes.Equal(local, remote) {
+			return true, nil
+		}
+	}
+
+	if extCfg.Store.Images == nil && extCfg.Store.ImageDirectory == nil {
+		return false, nil
+	}
+
+	localImages, err := configuredStoreImages(zipExt.GetPath(), extCfg)
+	if err != nil {
+		return false, err
+	}
+
+	remoteImages, err := p.GetExtensionImages(ctx, storeExt.Id)
+	if err != nil {
+		return false, fmt.Errorf("cannot get images from remote server: %w", err)
+	}
+
+	if len(localImages) != len(remoteImages) {
+		return true, nil
+	}
+
+	remoteKeys := make(map[string]int, len(remoteImages))
+
+	for _, image := range remoteImages {
+		content, err := p.DownloadStoreMedia(ctx, image.RemoteLink)
+		if err != nil {
+			return false, fmt.Errorf("cannot download store image: %w", err)
+		}
+
+		remoteKeys[storeMediaKey(content, image.Priority)]++
+	}
+
+	for _, image := range localImages {
+		content, err := os.ReadFile(image.File)
+		if err != nil {
+			return false, fmt.Errorf("cannot read store image: %w", err)
+		}
+
+		key := storeMediaKey(content, image.Priority)
+
+		if remoteKeys[key] == 0 {
+			return true, nil
+		}
+
+		remoteKeys[key]--
+	}
+
+	return false, nil
+}
+
+// configuredStoreImages returns all images pushStoreMedia uploads, independent of their activation per language.
+// At least one of images or image_directory has to be configured.
+func configuredStoreImages(extensionDir string, extCfg *Config) ([]StorePreviewImage, error) {
+	images := make([]StorePreviewImage, 0)
+
+	if extCfg.Store.ImageDirectory == nil {
+		for _, configImage := range *extCfg.Store.Images {
+			images = append(images, StorePreviewImage{File: filepath.Join(extensionDir, configImage.File), Priority: configImage.Priority})
+		}
+
+		return images, nil
+	}
+
+	for _, language := range ConfigLanguages {
+		directory := filepath.Join(extensionDir, *extCfg.Store.ImageDirectory, language)
+
+		entries, err := os.ReadDir(directory)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			priority := 0
+
+			if matches := storeImageNameRegExp.FindStringSubmatch(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))); matches != nil {
+				priority, _ = strconv.Atoi(matches[1])
+			}
+
+			images = append(images, StorePreviewImage{File: filepath.Join(directory, entry.Name()), Priority: priority})
+		}
+	}
+
+	return images, nil
+}
+
+func storeMediaKey(content []byte, priority int) string {
+	return fmt.Sprintf("%x-%d", sha256.Sum256(content), priority)
+}
+
+func pushStoreMedia(ctx context.Context, p *accountApi.ProducerEndpoint, extensionId int, zipExt Extension, extCfg *Config) error {
+	if extCfg.Store.Icon != nil {
+		err := p.UpdateExtensionIcon(ctx, extensionId, fmt.Sprintf("%s/%s", zipExt.GetPath(), *extCfg.Store.Icon))
+		if err != nil {
+			return fmt.Errorf("cannot update extension icon due error: %w", err)
+		}
+	}
+
+	if extCfg.Store.Images == nil && extCfg.Store.ImageDirectory == nil {
+		return nil
+	}
+
+	images, err := p.GetExtensionImages(ctx, extensionId)
+	if err != nil {
+		return fmt.Errorf("cannot get images from remote server: %w", err)
+	}
+
+	for _, image := range images {
+		err := p.DeleteExtensionImages(ctx, extensionId, image.Id)
+		if err != nil {
+			return fmt.Errorf("cannot extension image: %w", err)
+		}
+	}
+
+	if extCfg.Store.ImageDirectory != nil {
+		for _, language := range ConfigLanguages {
+			if err := uploadImagesByDirectory(ctx, extensionId, path.Join(zipExt.GetPath(), *extCfg.Store.ImageDirectory, language), language, p); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// manually specified images
+	for _, configImage := range *extCfg.Store.Images {
+		apiImage, err := p.AddExtensionImage(ctx, extensionId, fmt.Sprintf("%s/%s", zipExt.GetPath(), configImage.File))
+		if err != nil {
+			return fmt.Errorf("cannot upload image %s to extension: %w", configImage.File, err)
+		}
+
+		apiImage.Priority = configImage.Priority
+
+		err = p.UpdateExtensionImage(ctx, extensionId, apiImage)
+
+		if err != nil {
+			return fmt.Errorf("cannot update image information of extension: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func updateStoreInfo(ext *accountApi.Extension, zipExt Extension, cfg *Config, info *accountApi.ExtensionGeneralInformation) error { //nolint:gocyclo
+	if problems := ValidateStoreLimits(cfg); len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+
+	if cfg.Store.DefaultLocale != nil {
+		for _, locale := range info.Locales {
+			if locale.Name == *cfg.Store.DefaultLocale {
+				ext.StandardLocale = locale
+			}
+		}
+	}
+
+	if cfg.Store.Categories != nil {
+		for _, category := range info.FutureCategories {
+			for _, configCategory := range *cfg.Store.Categories {
+				if category.Name == configCategory {
+					selectCategory := category
+					ext.Category = &selectCategory
+					break
+				}
+			}
+		}
+	}
+
+	if cfg.Store.Type != nil {
+		for i, storeProductType := range info.ProductTypes {
+			if storeProductType.Name == *cfg.Store.Type {
+				ext.ProductType = &info.ProductTypes[i]
+			}
+		}
+	}
+
+	if cfg.Store.Price != nil {
+		ext.PriceModels = &accountApi.StorePrice{
+			Type:  cfg.Store.Price.Type,
+			Money: cfg.Store.Price.Money,
+		}
+	}
+
+	if cfg.Store.AutomaticBugfixVersionCompatibility != nil {
+		ext.AutomaticBugfixVersionCompatibility = *cfg.Store.AutomaticBugfixVersionCompatibility
+	}
+
+	for _, info := range ext.Infos {
+		language := LanguageFromLocale(info.Locale.Name)
+
+		storeTags := cfg.Store.Tags.Get(language)
+		if storeTags != nil {
+			var newTags []accountApi.StoreTag
+			for _, tag := range *storeTags {
+				newTags = append(newTags, accountApi.StoreTag{Name: tag})
+			}
+
+			info.Tags = newTags
+		}
+
+		storeVideos := cfg.Store.Videos.Get(language)
+		if storeVideos != nil {
+			var newVideos []accountApi.StoreVideo
+			for _, video := range *storeVideos {
+				newVideos = append(newVideos, accountApi.StoreVideo{URL: video})
+			}
+
+			info.Videos = newVideos
+		}
+
+		storeHighlights := cfg.Store.Highlights.Get(language)
+		if storeHighlights != nil {
+			info.Highlights = strings.Join(*storeHighlights, "\n")
+		}
+
+		storeFeatures := cfg.Store.Features.Get(language)
+		if storeFeatures != nil {
+			info.Features = strings.Join(*storeFeatures, "\n")
+		}
+
+		storeFaqs := cfg.Store.Faq.Get(language)
+		if storeFaqs != nil {
+			var newFaq []accountApi.StoreFaq
+			for _, faq := range *storeFaqs {
+				newFaq = append(newFaq, accountApi.StoreFaq{Question: faq.Question, Answer: faq.Answer})
+			}
+
+			info.Faqs = newFaq
+		}
+
+		var err error
+
+		storeDescription := cfg.Store.Description.Get(language)
+		if storeDescription != nil {
+			info.Description, err = ReadInlineablePath(*storeDescription, zipExt.GetPath())
+
+			if err != nil {
+				return err
+			}
+		}
+
+		storeManual := cfg.Store.InstallationManual.Get(language)
+		if storeManual != nil {
+			info.InstallationManual, err = ReadInlineablePath(*storeManual, zipExt.GetPath())
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// activateImageForLanguage shows the image only for the locales of the language folder it was uploaded from.
+func activateImageForLanguage(image *accountApi.ExtensionImage, language string) {
+	for i := range image.Details {
+		image.Details[i].Activated = LanguageFromLocale(image.Details[i].Locale.Name) == language
+	}
+}
+
+func uploadImagesByDirectory(ctx context.Context, extensionId int, directory, language string, p *accountApi.ProducerEndpoint) error {
+	images, err := os.ReadDir(directory)
+
+	// When folder does not exists, skip
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	for _, image := range images {
+		if image.IsDir() {
+			continue
+		}
+
+		fileName := image.Name()
+		fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+
+		apiImage, err := p.AddExtensionImage(ctx, extensionId, path.Join(directory, image.Name()))
+
+		if err != nil {
+			return fmt.Errorf("cannot upload image %s to extension: %w", image.Name(), err)
+		}
+
+		matches := storeImageNameRegExp.FindStringSubmatch(fileName)
+
+		if matches == nil {
+			logging.FromContext(ctx).Warnf("Invalid image name %s, skipping", image.Name())
+			continue
+		}
+
+		priority, err := strconv.Atoi(matches[1])
+
+		if err != nil {
+			logging.FromContext(ctx).Warnf("Unexpected error: \"%s\", skipping", err)
+			continue
+		}
+
+		apiImage.Priority = priority
+		activateImageForLanguage(apiImage, language)
+
+		if err := p.UpdateExtensionImage(ctx, extensionId, apiImage); err != nil {
+			return fmt.Errorf("cannot update image information of extension: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package extension
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	accountApi "github.com/haokeyingxiao/haoke-cli/account-api"
+)
+
+func TestConfiguredStoreImagesOfDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "images", "en"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "images", "de"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "images", "en", "2-overview.png"), []byte("en"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "images", "de", "1.png"), []byte("de"), os.ModePerm))
+
+	imageDirectory := "images"
+	cfg := &Config{}
+	cfg.Store.ImageDirectory = &imageDirectory
+
+	images, err := configuredStoreImages(dir, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []StorePreviewImage{
+		{File: filepath.Join(dir, "images", "de", "1.png"), Priority: 1},
+		{File: filepath.Join(dir, "images", "en", "2-overview.png"), Priority: 2},
+	}, images)
+}
+
+func TestConfiguredStoreImagesOfConfig(t *testing.T) {
+	cfg := &Config{}
+	cfg.Store.Images = &[]ConfigStoreImage{{File: "a.png", Priority: 3}}
+
+	images, err := configuredStoreImages("/ext", cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []StorePreviewImage{{File: filepath.Join("/ext", "a.png"), Priority: 3}}, images)
+}
+
+func TestStoreMediaKey(t *testing.T) {
+	assert.Equal(t, storeMediaKey([]byte("a"), 1), storeMediaKey([]byte("a"), 1))
+	assert.NotEqual(t, storeMediaKey([]byte("a"), 1), storeMediaKey([]byte("a"), 2))
+	assert.NotEqual(t, storeMediaKey([]byte("a"), 1), storeMediaKey([]byte("b"), 1))
+}
+
+func TestActivateImageForLanguage(t *testing.T) {
+	var image accountApi.ExtensionImage
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"id":"1","details":[
+		{"id":1,"activated":false,"locale":{"id":1,"name":"de_DE"}},
+		{"id":2,"activated":true,"locale":{"id":2,"name":"en_GB"}},
+		{"id":3,"activated":false,"locale":{"id":3,"name":"zh_CN"}}
+	],"priority":1}`), &image))
+
+	activateImageForLanguage(&image, "de")
+
+	body, err := json.Marshal(image)
+	assert.NoError(t, err)
+
+	var sent accountApi.ExtensionImage
+	assert.NoError(t, json.Unmarshal(body, &sent))
+
+	activated := make(map[string]bool)
+	for _, detail := range sent.Details {
+		activated[detail.Locale.Name] = detail.Activated
+	}
+
+	assert.Equal(t, map[string]bool{"de_DE": true, "en_GB": false, "zh_CN": false}, activated)
+}
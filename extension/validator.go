@@ -104,6 +104,12 @@ func runDefaultValidate(context *ValidationContext) {
 		return nil
 	})
 
+	if config := context.Extension.GetExtensionConfig(); config != nil {
+		for _, problem := range ValidateStoreLimits(config) {
+			context.AddWarning(problem)
+		}
+	}
+
 	metaData := context.Extension.GetMetaData()
 
 	if len(metaData.Label.German) == 0 {
@@ -0,0 +1,150 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/haokeyingxiao/haoke-cli/logging"
+	"github.com/haokeyingxiao/haoke-cli/version"
+)
+
+// VendorConflict is a package bundled into the vendor folder of the extension which is also installed by the shop.
+type VendorConflict struct {
+	Package        string
+	BundledVersion string
+	ShopVersion    string
+	// Incompatible is set when the major versions differ, the autoloader loads only one of both
+	Incompatible bool
+	// NotExcluded is set when the extension requires the package directly without listing it in excluded_packages
+	NotExcluded bool
+}
+
+func (c VendorConflict) String() string {
+	message := fmt.Sprintf("package %s is bundled in version %s, but the shop already installs %s", c.Package, c.BundledVersion, c.ShopVersion)
+
+	if c.Incompatible {
+		message += ", the versions are incompatible"
+	}
+
+	if c.NotExcluded {
+		message += ", add it to build.zip.composer.excluded_packages"
+	}
+
+	return message
+}
+
+func readComposerLockPackages(lockFile string) (map[string]string, error) {
+	content, err := os.ReadFile(lockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock composerLock
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", lockFile, err)
+	}
+
+	packages := make(map[string]string, len(lock.Packages))
+
+	for _, pkg := range lock.Packages {
+		packages[pkg.Name] = pkg.Version
+	}
+
+	return packages, nil
+}
+
+// shopComposerPackages returns the packages installed by the core of the minimum version.
+// With SHOPWARE_PROJECT_ROOT the composer.lock of the project is used, which contains also the other plugins.
+func shopComposerPackages(ctx context.Context, minVersion string) (map[string]string, error) {
+	if projectRoot := os.Getenv("SHOPWARE_PROJECT_ROOT"); projectRoot != "" {
+		if _, err := os.Stat(filepath.Join(projectRoot, "composer.lock")); err == nil {
+			return readComposerLockPackages(filepath.Join(projectRoot, "composer.lock"))
+		}
+	}
+
+	packages := map[string]string{}
+
+	for _, component := range []string{"core", "administration", "storefront"} {
+		componentPackages, err := fetchComponentPackages(ctx, minVersion, component)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, packageVersion := range componentPackages {
+			packages[name] = packageVersion
+		}
+	}
+
+	return packages, nil
+}
+
+// DetectVendorConflicts compares the bundled packages with the packages of the shop.
+// requires are the direct requirements of the extension after removing the excluded packages.
+func DetectVendorConflicts(bundled, shop map[string]string, requires map[string]interface{}) []VendorConflict {
+	conflicts := make([]VendorConflict, 0)
+
+	for name, bundledVersion := range bundled {
+		shopVersion, ok := shop[name]
+		if !ok {
+			continue
+		}
+
+		_, directRequire := requires[name]
+
+		conflicts = append(conflicts, VendorConflict{
+			Package:        name,
+			BundledVersion: bundledVersion,
+			ShopVersion:    shopVersion,
+			Incompatible:   !isCompatibleVersion(bundledVersion, shopVersion),
+			NotExcluded:    directRequire,
+		})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Package < conflicts[j].Package
+	})
+
+	return conflicts
+}
+
+// isCompatibleVersion compares the major versions, for 0.x versions the minor version. Unknown versions like branches are treated as compatible.
+func isCompatibleVersion(a, b string) bool {
+	versionA, errA := version.NewVersion(a)
+	versionB, errB := version.NewVersion(b)
+
+	if errA != nil || errB != nil {
+		return true
+	}
+
+	segmentsA := versionA.Segments()
+	segmentsB := versionB.Segments()
+
+	if segmentsA[0] != segmentsB[0] {
+		return false
+	}
+
+	return segmentsA[0] != 0 || segmentsA[1] == segmentsB[1]
+}
+
+// warnVendorConflicts logs the packages of the freshly installed vendor folder which clash with the shop.
+func warnVendorConflicts(ctx context.Context, folder, minVersion string, requires map[string]interface{}) {
+	bundled, err := readComposerLockPackages(filepath.Join(folder, "composer.lock"))
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot check the bundled packages for conflicts: %v", err)
+		return
+	}
+
+	shop, err := shopComposerPackages(ctx, minVersion)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot check the bundled packages for conflicts: %v", err)
+		return
+	}
+
+	for _, conflict := range DetectVendorConflicts(bundled, shop, requires) {
+		logging.FromContext(ctx).Warnf("Vendor conflict: %s", conflict)
+	}
+}
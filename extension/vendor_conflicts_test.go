@@ -0,0 +1,53 @@
+package extension
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectVendorConflicts(t *testing.T) {
+	bundled := map[string]string{
+		"symfony/yaml":      "v7.0.0",
+		"psr/log":           "1.1.4",
+		"league/csv":        "9.15.0",
+		"guzzlehttp/psr7":   "2.6.2",
+		"nikic/php-parser":  "v0.9.0",
+		"some/branch":       "dev-main",
+		"not/in/core/other": "1.0.0",
+	}
+
+	shop := map[string]string{
+		"symfony/yaml":     "v6.4.0",
+		"psr/log":          "1.1.0",
+		"guzzlehttp/psr7":  "*",
+		"nikic/php-parser": "v0.10.0",
+		"some/branch":      "1.0.0",
+	}
+
+	conflicts := DetectVendorConflicts(bundled, shop, map[string]interface{}{"symfony/yaml": "^7.0"})
+
+	assert.Equal(t, []VendorConflict{
+		{Package: "guzzlehttp/psr7", BundledVersion: "2.6.2", ShopVersion: "*"},
+		{Package: "nikic/php-parser", BundledVersion: "v0.9.0", ShopVersion: "v0.10.0", Incompatible: true},
+		{Package: "psr/log", BundledVersion: "1.1.4", ShopVersion: "1.1.0"},
+		{Package: "some/branch", BundledVersion: "dev-main", ShopVersion: "1.0.0"},
+		{Package: "symfony/yaml", BundledVersion: "v7.0.0", ShopVersion: "v6.4.0", Incompatible: true, NotExcluded: true},
+	}, conflicts)
+
+	assert.Equal(t, "package symfony/yaml is bundled in version v7.0.0, but the shop already installs v6.4.0, the versions are incompatible, add it to build.zip.composer.excluded_packages", conflicts[4].String())
+}
+
+func TestShopComposerPackagesOfProject(t *testing.T) {
+	projectRoot := t.TempDir()
+	t.Setenv("SHOPWARE_PROJECT_ROOT", projectRoot)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(projectRoot, "composer.lock"), []byte(`{"packages":[{"name":"symfony/yaml","version":"v6.4.0"}],"packages-dev":[{"name":"phpunit/phpunit","version":"10.0.0"}]}`), os.ModePerm))
+
+	packages, err := shopComposerPackages(context.Background(), "6.5.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"symfony/yaml": "v6.4.0"}, packages)
+}
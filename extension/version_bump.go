@@ -0,0 +1,216 @@
+package extension
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/changelog"
+	"github.com/haokeyingxiao/haoke-cli/internal/git"
+	"github.com/haokeyingxiao/haoke-cli/version"
+)
+
+const (
+	VersionBumpMajor = "major"
+	VersionBumpMinor = "minor"
+	VersionBumpPatch = "patch"
+	VersionBumpAuto  = "auto"
+)
+
+var (
+	VersionBumps = []string{VersionBumpMajor, VersionBumpMinor, VersionBumpPatch, VersionBumpAuto}
+
+	manifestVersionPattern = regexp.MustCompile(`(<version>\s*)[^<]*?(\s*</version>)`)
+
+	// commit types without an effect for the users of the extension
+	ignoredChangelogCommitTypes = map[string]bool{"chore": true, "ci": true, "build": true, "test": true, "style": true, "docs": true}
+)
+
+// BumpVersion returns the next version, prerelease and metadata are dropped.
+func BumpVersion(current *version.Version, bump string) (*version.Version, error) {
+	segments := append(current.Segments(), 0, 0, 0)[:3]
+
+	switch bump {
+	case VersionBumpMajor:
+		segments = []int{segments[0] + 1, 0, 0}
+	case VersionBumpMinor:
+		segments = []int{segments[0], segments[1] + 1, 0}
+	case VersionBumpPatch:
+		segments[2]++
+	default:
+		return nil, fmt.Errorf("unknown version bump %q, use one of %s", bump, strings.Join(VersionBumps, ", "))
+	}
+
+	return version.NewVersion(fmt.Sprintf("%d.%d.%d", segments[0], segments[1], segments[2]))
+}
+
+// DetectVersionBump derives the bump from conventional commits: breaking changes are major, features minor and everything else patch.
+func DetectVersionBump(commits []git.GitCommit) string {
+	bump := VersionBumpPatch
+
+	for _, commit := range commits {
+		parsed, ok := changelog.ParseConventionalCommit(commit)
+		if !ok {
+			continue
+		}
+
+		if parsed.Breaking {
+			return VersionBumpMajor
+		}
+
+		if parsed.Type == "feat" {
+			bump = VersionBumpMinor
+		}
+	}
+
+	return bump
+}
+
+// ChangelogEntriesOfCommits returns one markdown list entry per commit which is relevant for users.
+func ChangelogEntriesOfCommits(commits []git.GitCommit) []string {
+	entries := make([]string, 0, len(commits))
+
+	for _, commit := range commits {
+		message := commit.Message
+
+		if parsed, ok := changelog.ParseConventionalCommit(commit); ok {
+			if ignoredChangelogCommitTypes[parsed.Type] {
+				continue
+			}
+
+			message = parsed.Description
+		}
+
+		entries = append(entries, fmt.Sprintf("- %s", message))
+	}
+
+	return entries
+}
+
+// VersionFile returns the file containing the version of the extension.
+func VersionFile(ext Extension) string {
+	if ext.GetType() == TypePlatformApp {
+		return filepath.Join(ext.GetPath(), "manifest.xml")
+	}
+
+	return filepath.Join(ext.GetPath(), "composer.json")
+}
+
+// WriteExtensionVersion replaces the version in the composer.json or manifest.xml and keeps the formatting of the file.
+func WriteExtensionVersion(ext Extension, newVersion *version.Version) error {
+	file := VersionFile(ext)
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if ext.GetType() == TypePlatformApp {
+		loc := manifestVersionPattern.FindSubmatchIndex(content)
+		if loc == nil {
+			return fmt.Errorf("manifest.xml does not contain a version")
+		}
+
+		content = bytes.Join([][]byte{content[:loc[3]], []byte(newVersion.String()), content[loc[4]:]}, nil)
+	} else {
+		content, err = replaceComposerVersion(content, newVersion.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(file, content, os.ModePerm)
+}
+
+// replaceComposerVersion replaces only the value of the top level version key.
+func replaceComposerVersion(content []byte, newVersion string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("composer.json is not a json object")
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start := decoder.InputOffset()
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if key != "version" {
+			continue
+		}
+
+		end := decoder.InputOffset()
+		valueStart := start + int64(bytes.LastIndex(content[start:end], value))
+
+		encoded, err := json.Marshal(newVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		return bytes.Join([][]byte{content[:valueStart], encoded, content[valueStart+int64(len(value)):]}, nil), nil
+	}
+
+	return nil, fmt.Errorf("composer.json does not contain a version")
+}
+
+// PrependChangelogVersion adds a section for the version to every CHANGELOG*.md, the english one is created when missing.
+// It returns the changed files, without entries an error is returned instead of writing an empty section.
+func PrependChangelogVersion(ext Extension, newVersion *version.Version, entries []string) ([]string, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no changelog entries for version %s", newVersion.String())
+	}
+
+	files, err := filepath.Glob(filepath.Join(ext.GetPath(), "CHANGELOG*.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		files = []string{filepath.Join(ext.GetPath(), "CHANGELOG_en-GB.md")}
+	}
+
+	section := fmt.Sprintf("# %s\n\n%s\n", newVersion.String(), strings.Join(entries, "\n"))
+	changed := make([]string, 0, len(files))
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		existing, err := parseMarkdownChangelog(string(content))
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := existing[newVersion.String()]; ok {
+			continue
+		}
+
+		if len(content) > 0 {
+			content = append([]byte(section+"\n"), content...)
+		} else {
+			content = []byte(section)
+		}
+
+		if err := os.WriteFile(file, content, os.ModePerm); err != nil {
+			return nil, err
+		}
+
+		changed = append(changed, file)
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,92 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/git"
+	"github.com/haokeyingxiao/haoke-cli/version"
+)
+
+func TestBumpVersion(t *testing.T) {
+	current := version.Must(version.NewVersion("1.2.3-beta"))
+
+	cases := map[string]string{
+		VersionBumpMajor: "2.0.0",
+		VersionBumpMinor: "1.3.0",
+		VersionBumpPatch: "1.2.4",
+	}
+
+	for bump, expected := range cases {
+		next, err := BumpVersion(current, bump)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, next.String())
+	}
+
+	_, err := BumpVersion(current, "huge")
+	assert.Error(t, err)
+}
+
+func TestDetectVersionBump(t *testing.T) {
+	assert.Equal(t, VersionBumpPatch, DetectVersionBump([]git.GitCommit{{Message: "fix: typo"}, {Message: "some change"}}))
+	assert.Equal(t, VersionBumpMinor, DetectVersionBump([]git.GitCommit{{Message: "fix: typo"}, {Message: "feat(admin): new module"}}))
+	assert.Equal(t, VersionBumpMajor, DetectVersionBump([]git.GitCommit{{Message: "feat!: drop old api"}}))
+	assert.Equal(t, VersionBumpMajor, DetectVersionBump([]git.GitCommit{{Message: "refactor: config", Body: "BREAKING CHANGE: config keys renamed"}}))
+
+	assert.Equal(t, []string{"- new module", "- not conventional"}, ChangelogEntriesOfCommits([]git.GitCommit{{Message: "feat(admin): new module"}, {Message: "chore: deps"}, {Message: "not conventional"}}))
+}
+
+func TestWriteExtensionVersionKeepsFormatting(t *testing.T) {
+	dir := t.TempDir()
+	composer := "{\n    \"name\": \"frosh/tools\",\n    \"version\": \"1.0.0\",\n    \"extra\": {\n        \"version\": \"nested\"\n    }\n}\n"
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(composer), os.ModePerm))
+
+	plugin := getTestPlugin(dir)
+	assert.NoError(t, WriteExtensionVersion(plugin, version.Must(version.NewVersion("1.1.0"))))
+
+	content, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n    \"name\": \"frosh/tools\",\n    \"version\": \"1.1.0\",\n    \"extra\": {\n        \"version\": \"nested\"\n    }\n}\n", string(content))
+
+	app := App{path: dir}
+	manifest := "<manifest>\n  <meta>\n    <version>1.0.0</version>\n  </meta>\n</manifest>\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.xml"), []byte(manifest), os.ModePerm))
+	assert.NoError(t, WriteExtensionVersion(app, version.Must(version.NewVersion("2.0.0"))))
+
+	content, err = os.ReadFile(filepath.Join(dir, "manifest.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "<manifest>\n  <meta>\n    <version>2.0.0</version>\n  </meta>\n</manifest>\n", string(content))
+}
+
+func TestPrependChangelogVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "CHANGELOG_en-GB.md"), []byte("# 1.0.0\n\n- Initial\n"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "CHANGELOG_de-DE.md"), []byte("# 1.0.0\n\n- Erste Version\n"), os.ModePerm))
+
+	plugin := getTestPlugin(dir)
+	next := version.Must(version.NewVersion("1.1.0"))
+
+	changed, err := PrependChangelogVersion(plugin, next, []string{"- new module"})
+	assert.NoError(t, err)
+	assert.Len(t, changed, 2)
+
+	content, err := os.ReadFile(filepath.Join(dir, "CHANGELOG_en-GB.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# 1.1.0\n\n- new module\n\n# 1.0.0\n\n- Initial\n", string(content))
+
+	changed, err = PrependChangelogVersion(plugin, next, []string{"- new module"})
+	assert.NoError(t, err)
+	assert.Len(t, changed, 0)
+
+	_, err = PrependChangelogVersion(plugin, version.Must(version.NewVersion("1.2.0")), nil)
+	assert.Error(t, err)
+
+	content, err = os.ReadFile(filepath.Join(dir, "CHANGELOG_en-GB.md"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "1.2.0")
+}
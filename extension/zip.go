@@ -4,6 +4,8 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -15,7 +17,9 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/haokeyingxiao/haoke-cli/internal/changelog"
 
@@ -122,7 +126,8 @@ func Unzip(r *zip.Reader, dest string) error {
 	return nil
 }
 
-func CreateZip(baseFolder, zipFile string) error {
+// CreateZip creates a reproducible zip of the folder, all entries get the modified time.
+func CreateZip(baseFolder, zipFile string, modified time.Time) error {
 	// Get a Buffer to Write To
 	outFile, err := os.Create(zipFile)
 	if err != nil {
@@ -136,35 +141,106 @@ func CreateZip(baseFolder, zipFile string) error {
 	// Create a new zip archive.
 	w := zip.NewWriter(outFile)
 
-	defer func() {
+	if err := AddZipFiles(w, baseFolder, "", modified); err != nil {
 		_ = w.Close()
-	}()
 
-	return AddZipFiles(w, baseFolder, "")
+		return err
+	}
+
+	return w.Close()
 }
 
-func AddZipFiles(w *zip.Writer, basePath, baseInZip string) error {
-	files, err := os.ReadDir(basePath)
+// AddZipFiles adds the files of the folder sorted by their path, a zero modified time keeps the time of the files.
+func AddZipFiles(w *zip.Writer, basePath, baseInZip string, modified time.Time) error {
+	files := make([]string, 0)
+
+	err := filepath.WalkDir(basePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("could not zip dir, basePath: %q, baseInZip: %q, %w", basePath, baseInZip, err)
 	}
 
+	zipPaths := make(map[string]string, len(files))
+
 	for _, file := range files {
-		if file.IsDir() {
-			// Add files of directory recursively
-			if err = AddZipFiles(w, filepath.Join(basePath, file.Name()), filepath.Join(baseInZip, file.Name())); err != nil {
-				return err
-			}
-		} else {
-			if err = addFileToZip(w, filepath.Join(basePath, file.Name()), filepath.Join(baseInZip, file.Name())); err != nil {
-				return err
-			}
+		relPath, err := filepath.Rel(basePath, file)
+		if err != nil {
+			return err
+		}
+
+		zipPaths[file] = filepath.ToSlash(filepath.Join(baseInZip, relPath))
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return zipPaths[files[i]] < zipPaths[files[j]]
+	})
+
+	for _, file := range files {
+		if err := addFileToZip(w, file, zipPaths[file], modified); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// zipEpoch is the earliest time which can be stored in a zip.
+var zipEpoch = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ZipModifiedTime returns the time for reproducible zips: SOURCE_DATE_EPOCH, the commit date of the reference or the earliest time of a zip.
+func ZipModifiedTime(source, reference string) time.Time {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if seconds, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC()
+		}
+	}
+
+	if reference == "" {
+		reference = "HEAD"
+	}
+
+	if commitTime, err := gitCommitTime(source, reference); err == nil {
+		return commitTime.UTC()
+	}
+
+	return zipEpoch
+}
+
+// WriteChecksumFile writes the sha256 of the file in the format of sha256sum next to it.
+func WriteChecksumFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	checksumFile := file + ".sha256"
+	content := fmt.Sprintf("%s  %s\n", hex.EncodeToString(hash.Sum(nil)), filepath.Base(file))
+
+	if err := os.WriteFile(checksumFile, []byte(content), 0o644); err != nil { //nolint:gosec
+		return "", err
+	}
+
+	return checksumFile, nil
+}
+
 func CleanupExtensionFolder(path string, additionalPaths []string) error {
 	defaultNotAllowedPaths = append(defaultNotAllowedPaths, additionalPaths...)
 
@@ -225,19 +301,12 @@ func PrepareFolderForZipping(ctx context.Context, path string, ext Extension, ex
 		return fmt.Errorf(errorFormat, err)
 	}
 
-	minShopwareVersionConstraint, err := ext.GetShopwareVersionConstraint()
-	if err != nil {
-		return fmt.Errorf(errorFormat, err)
-	}
-
-	minVersion, err := lookupForMinMatchingVersion(ctx, minShopwareVersionConstraint)
+	minVersion, needsReplacements, err := LookupMinShopwareVersion(ctx, ext)
 	if err != nil {
 		return fmt.Errorf("lookup for min matching version: %w", err)
 	}
 
-	shopware65Constraint, _ := version.NewConstraint(">=6.6.0")
-
-	if shopware65Constraint.Check(version.Must(version.NewVersion(minVersion))) {
+	if !needsReplacements {
 		logging.FromContext(ctx).Info("haoke 6.6 detected, disabling composer replacements")
 		return nil
 	}
@@ -285,12 +354,14 @@ func PrepareFolderForZipping(ctx context.Context, path string, ext Extension, ex
 		return fmt.Errorf(errorFormat, err)
 	}
 
+	warnVendorConflicts(ctx, path, minVersion, filtered["require"].(map[string]interface{}))
+
 	_ = os.WriteFile(composerJSONPath, content, 0o644) //nolint:gosec
 
 	return nil
 }
 
-func addFileToZip(zipWriter *zip.Writer, sourcePath string, zipPath string) error {
+func addFileToZip(zipWriter *zip.Writer, sourcePath string, zipPath string, modified time.Time) error {
 	zipErrorFormat := "could not zip file, sourcePath: %q, zipPath: %q, %w"
 
 	file, err := os.Open(sourcePath)
@@ -314,6 +385,17 @@ func addFileToZip(zipWriter *zip.Writer, sourcePath string, zipPath string) erro
 	header.Name = zipPath
 	header.Method = zip.Deflate
 
+	if !modified.IsZero() {
+		header.Modified = modified
+	}
+
+	// the permissions depend on the umask, only the executable flag is kept
+	if fileInfo.Mode()&0o111 != 0 {
+		header.SetMode(0o755)
+	} else {
+		header.SetMode(0o644)
+	}
+
 	f, err := zipWriter.CreateHeader(header)
 	if err != nil {
 		return fmt.Errorf(zipErrorFormat, sourcePath, zipPath, err)
@@ -370,27 +452,9 @@ func addComposerReplacements(ctx context.Context, composer map[string]interface{
 		packageName := fmt.Sprintf("shopware/%s", component)
 
 		if _, ok := require.(map[string]interface{})[packageName]; ok {
-			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://swagger.docs.fos.gg/composer/%s/%s.json", minVersion, component), http.NoBody)
+			composerPart, err := fetchComponentPackages(ctx, minVersion, component)
 			if err != nil {
-				return nil, fmt.Errorf("create component request: %w", err)
-			}
-
-			resp, err := http.DefaultClient.Do(req)
-			if err != nil {
-				return nil, fmt.Errorf("get packte version %s: %w", component, err)
-			}
-
-			composerPartByte, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return nil, fmt.Errorf("read component version body: %w", err)
-			}
-
-			_ = resp.Body.Close()
-
-			var composerPart map[string]string
-			err = json.Unmarshal(composerPartByte, &composerPart)
-			if err != nil {
-				return nil, fmt.Errorf("unmarshal component version: %w", err)
+				return nil, err
 			}
 
 			for k, v := range composerPart {
@@ -408,13 +472,43 @@ func addComposerReplacements(ctx context.Context, composer map[string]interface{
 	return composer, nil
 }
 
+// fetchComponentPackages returns the packages with their versions shipped by the component of the core version.
+func fetchComponentPackages(ctx context.Context, minVersion, component string) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://swagger.docs.fos.gg/composer/%s/%s.json", minVersion, component), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("create component request: %w", err)
+	}
+
+	resp, err := composerVersionsClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get packte version %s: %w", component, err)
+	}
+
+	composerPartByte, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read component version body: %w", err)
+	}
+
+	_ = resp.Body.Close()
+
+	var composerPart map[string]string
+	err = json.Unmarshal(composerPartByte, &composerPart)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal component version: %w", err)
+	}
+
+	return composerPart, nil
+}
+
+var composerVersionsClient = &http.Client{Timeout: 30 * time.Second}
+
 func lookupForMinMatchingVersion(ctx context.Context, versionConstraint *version.Constraints) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://swagger.docs.fos.gg/composer/versions.json", http.NoBody)
 	if err != nil {
 		return "", fmt.Errorf("create composer version request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := composerVersionsClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("fetch composer versions: %w", err)
 	}
@@ -487,15 +581,17 @@ func PrepareExtensionForRelease(ctx context.Context, sourceRoot, extensionRoot s
 
 		logging.FromContext(ctx).Infof("Generated changelog for version %s", v.String())
 
-		content, err := changelog.GenerateChangelog(ctx, sourceRoot, ext.GetExtensionConfig().Changelog)
+		changelogs, err := changelog.GenerateChangelogs(ctx, sourceRoot, ext.GetExtensionConfig().Changelog)
 		if err != nil {
 			return err
 		}
 
-		changelogFile := fmt.Sprintf("# %s\n%s", v.String(), content)
+		for language, content := range changelogs {
+			changelogFile := fmt.Sprintf("# %s\n%s", v.String(), content)
 
-		if err := os.WriteFile(path.Join(extensionRoot, "CHANGELOG_en-GB.md"), []byte(changelogFile), os.ModePerm); err != nil {
-			return err
+			if err := os.WriteFile(path.Join(extensionRoot, fmt.Sprintf("CHANGELOG_%s.md", language)), []byte(changelogFile), os.ModePerm); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -0,0 +1,309 @@
+package extension
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ZipEntry is a file inside an extension zip.
+type ZipEntry struct {
+	Path           string `json:"path"`
+	Size           uint64 `json:"size"`
+	CompressedSize uint64 `json:"compressedSize"`
+	CRC32          uint32 `json:"crc32"`
+	// NotAllowed contains the reason why the store or the zip command would reject the file
+	NotAllowed string `json:"notAllowed,omitempty"`
+}
+
+// ZipMetadataChange is a changed field of the composer.json or manifest.xml.
+type ZipMetadataChange struct {
+	File  string `json:"file"`
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ZipDiff contains the differences between two extension zips.
+type ZipDiff struct {
+	Added    []ZipEntry          `json:"added"`
+	Removed  []ZipEntry          `json:"removed"`
+	Changed  []ZipEntry          `json:"changed"`
+	Metadata []ZipMetadataChange `json:"metadata"`
+}
+
+// InspectZip lists the files of the zip sorted by path and flags files which are not allowed.
+func InspectZip(zipFile string) ([]ZipEntry, error) {
+	r, err := zip.OpenReader(zipFile)
+	if err != nil {
+		return nil, fmt.Errorf("open zip: %w", err)
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	return zipEntries(&r.Reader), nil
+}
+
+func zipEntries(r *zip.Reader) []ZipEntry {
+	entries := make([]ZipEntry, 0, len(r.File))
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		entries = append(entries, ZipEntry{
+			Path:           f.Name,
+			Size:           f.UncompressedSize64,
+			CompressedSize: f.CompressedSize64,
+			CRC32:          f.CRC32,
+			NotAllowed:     notAllowedReason(f.Name),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	return entries
+}
+
+// notAllowedReason applies the rules of CleanupExtensionFolder to a path of the zip, the first folder is the extension name.
+func notAllowedReason(zipPath string) string {
+	_, relPath, found := strings.Cut(zipPath, "/")
+	if !found {
+		relPath = zipPath
+	}
+
+	return cleanupReason(relPath)
+}
+
+// cleanupReason returns why CleanupExtensionFolder removes the path relative to the extension root.
+func cleanupReason(relPath string) string {
+	for _, segment := range strings.Split(relPath, "/") {
+		if segment == ".." {
+			return "path traversal"
+		}
+	}
+
+	for _, notAllowed := range defaultNotAllowedPaths {
+		if isPathOrChild(relPath, notAllowed) {
+			return fmt.Sprintf("path %s is not allowed", notAllowed)
+		}
+	}
+
+	for _, segment := range strings.Split(relPath, "/") {
+		for _, notAllowed := range defaultNotAllowedFiles {
+			if segment == notAllowed {
+				return fmt.Sprintf("file %s is not allowed", notAllowed)
+			}
+		}
+	}
+
+	base := path.Base(relPath)
+
+	for _, ext := range defaultNotAllowedExtensions {
+		if strings.HasSuffix(base, ext) {
+			return fmt.Sprintf("files with extension %s are not allowed", ext)
+		}
+	}
+
+	return ""
+}
+
+func isPathOrChild(relPath, folder string) bool {
+	folder = strings.Trim(folder, "/")
+
+	return relPath == folder || strings.HasPrefix(relPath, folder+"/")
+}
+
+// DiffZips compares the files and the composer.json / manifest.xml metadata of two extension zips.
+func DiffZips(oldZip, newZip string) (*ZipDiff, error) {
+	oldReader, err := zip.OpenReader(oldZip)
+	if err != nil {
+		return nil, fmt.Errorf("open zip %s: %w", oldZip, err)
+	}
+
+	defer func() {
+		_ = oldReader.Close()
+	}()
+
+	newReader, err := zip.OpenReader(newZip)
+	if err != nil {
+		return nil, fmt.Errorf("open zip %s: %w", newZip, err)
+	}
+
+	defer func() {
+		_ = newReader.Close()
+	}()
+
+	diff := &ZipDiff{
+		Added:    []ZipEntry{},
+		Removed:  []ZipEntry{},
+		Changed:  []ZipEntry{},
+		Metadata: []ZipMetadataChange{},
+	}
+
+	oldEntries := map[string]ZipEntry{}
+	for _, entry := range zipEntries(&oldReader.Reader) {
+		oldEntries[entryPathInExtension(entry.Path)] = entry
+	}
+
+	newEntries := zipEntries(&newReader.Reader)
+	seen := map[string]bool{}
+
+	for _, entry := range newEntries {
+		key := entryPathInExtension(entry.Path)
+		seen[key] = true
+
+		oldEntry, ok := oldEntries[key]
+
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, entry)
+		case oldEntry.CRC32 != entry.CRC32 || oldEntry.Size != entry.Size:
+			diff.Changed = append(diff.Changed, entry)
+		}
+	}
+
+	for _, entry := range zipEntries(&oldReader.Reader) {
+		if !seen[entryPathInExtension(entry.Path)] {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+
+	for _, file := range []string{"composer.json", "manifest.xml"} {
+		oldFields, err := zipMetadataFields(&oldReader.Reader, file)
+		if err != nil {
+			return nil, err
+		}
+
+		newFields, err := zipMetadataFields(&newReader.Reader, file)
+		if err != nil {
+			return nil, err
+		}
+
+		diff.Metadata = append(diff.Metadata, diffMetadataFields(file, oldFields, newFields)...)
+	}
+
+	return diff, nil
+}
+
+// entryPathInExtension removes the extension folder, so renamed extensions can be compared too.
+func entryPathInExtension(zipPath string) string {
+	if _, relPath, found := strings.Cut(zipPath, "/"); found {
+		return relPath
+	}
+
+	return zipPath
+}
+
+func zipMetadataFields(r *zip.Reader, file string) (map[string]string, error) {
+	var content []byte
+
+	for _, f := range r.File {
+		if entryPathInExtension(f.Name) != file {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", f.Name, err)
+		}
+
+		content, err = io.ReadAll(rc)
+		_ = rc.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", f.Name, err)
+		}
+
+		break
+	}
+
+	fields := map[string]string{}
+
+	if content == nil {
+		return fields, nil
+	}
+
+	if file == "manifest.xml" {
+		var manifest appManifest
+		if err := xml.Unmarshal(content, &manifest); err != nil {
+			return nil, fmt.Errorf("parse manifest.xml: %w", err)
+		}
+
+		fields["meta.name"] = manifest.Meta.Name
+		fields["meta.version"] = manifest.Meta.Version
+		fields["meta.license"] = manifest.Meta.License
+		fields["meta.author"] = manifest.Meta.Author
+		fields["meta.compatibility"] = manifest.Meta.Compatibility
+		fields["setup.registrationUrl"] = manifest.Setup.RegistrationUrl
+		fields["permissions.read"] = strings.TrimSpace(manifest.Permissions.Read)
+		fields["permissions.create"] = strings.TrimSpace(manifest.Permissions.Create)
+		fields["permissions.update"] = strings.TrimSpace(manifest.Permissions.Update)
+		fields["permissions.delete"] = strings.TrimSpace(manifest.Permissions.Delete)
+
+		return fields, nil
+	}
+
+	var composer map[string]interface{}
+	if err := json.Unmarshal(content, &composer); err != nil {
+		return nil, fmt.Errorf("parse composer.json: %w", err)
+	}
+
+	flattenJSON("", composer, fields)
+
+	return fields, nil
+}
+
+func flattenJSON(prefix string, value interface{}, fields map[string]string) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+
+			flattenJSON(key, child, fields)
+		}
+	case string:
+		fields[prefix] = typed
+	default:
+		encoded, _ := json.Marshal(typed)
+		fields[prefix] = string(encoded)
+	}
+}
+
+func diffMetadataFields(file string, oldFields, newFields map[string]string) []ZipMetadataChange {
+	keys := make([]string, 0, len(oldFields)+len(newFields))
+
+	for key := range oldFields {
+		keys = append(keys, key)
+	}
+
+	for key := range newFields {
+		if _, ok := oldFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	changes := make([]ZipMetadataChange, 0)
+
+	for _, key := range keys {
+		if oldFields[key] != newFields[key] {
+			changes = append(changes, ZipMetadataChange{File: file, Field: key, Old: oldFields[key], New: newFields[key]})
+		}
+	}
+
+	return changes
+}
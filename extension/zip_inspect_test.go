@@ -0,0 +1,81 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	source := t.TempDir()
+
+	for name, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(source, name)), os.ModePerm))
+		assert.NoError(t, os.WriteFile(filepath.Join(source, name), []byte(content), os.ModePerm))
+	}
+
+	zipFile := filepath.Join(t.TempDir(), "test.zip")
+	assert.NoError(t, CreateZip(source, zipFile, time.Time{}))
+
+	return zipFile
+}
+
+func TestInspectZipFlagsNotAllowedFiles(t *testing.T) {
+	zipFile := createTestZip(t, map[string]string{
+		"FroshTools/composer.json":             "{}",
+		"FroshTools/tests/FooTest.php":         "<?php",
+		"FroshTools/src/.DS_Store":             "",
+		"FroshTools/src/Resources/archive.zip": "",
+		"FroshTools/src/Service/tests.php":     "<?php",
+	})
+
+	entries, err := InspectZip(zipFile)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 5)
+
+	reasons := map[string]string{}
+	for _, entry := range entries {
+		reasons[entry.Path] = entry.NotAllowed
+	}
+
+	assert.Equal(t, "", reasons["FroshTools/composer.json"])
+	assert.Equal(t, "", reasons["FroshTools/src/Service/tests.php"])
+	assert.Equal(t, "path tests is not allowed", reasons["FroshTools/tests/FooTest.php"])
+	assert.Equal(t, "file .DS_Store is not allowed", reasons["FroshTools/src/.DS_Store"])
+	assert.Equal(t, "files with extension .zip are not allowed", reasons["FroshTools/src/Resources/archive.zip"])
+}
+
+func TestDiffZips(t *testing.T) {
+	oldZip := createTestZip(t, map[string]string{
+		"FroshTools/composer.json":     `{"version": "1.0.0", "require": {"haokeyingxiao/core": "~6.5.0"}}`,
+		"FroshTools/src/Plugin.php":    "<?php",
+		"FroshTools/src/Removed.php":   "<?php",
+		"FroshTools/src/Unchanged.php": "<?php",
+	})
+
+	newZip := createTestZip(t, map[string]string{
+		"FroshTools/composer.json":     `{"version": "1.1.0", "require": {"haokeyingxiao/core": "~6.5.0", "php": ">=8.1"}}`,
+		"FroshTools/src/Plugin.php":    "<?php echo 1;",
+		"FroshTools/src/Added.php":     "<?php",
+		"FroshTools/src/Unchanged.php": "<?php",
+	})
+
+	diff, err := DiffZips(oldZip, newZip)
+	assert.NoError(t, err)
+
+	assert.Len(t, diff.Added, 1)
+	assert.Equal(t, "FroshTools/src/Added.php", diff.Added[0].Path)
+	assert.Len(t, diff.Removed, 1)
+	assert.Equal(t, "FroshTools/src/Removed.php", diff.Removed[0].Path)
+	assert.Len(t, diff.Changed, 2)
+
+	assert.Equal(t, []ZipMetadataChange{
+		{File: "composer.json", Field: "require.php", Old: "", New: ">=8.1"},
+		{File: "composer.json", Field: "version", Old: "1.0.0", New: "1.1.0"},
+	}, diff.Metadata)
+}
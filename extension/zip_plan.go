@@ -0,0 +1,149 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/haokeyingxiao/haoke-cli/version"
+)
+
+const (
+	ZipPlanReasonPackExclude    = "pack excludes"
+	ZipPlanReasonDefaultCleanup = "default cleanup"
+	ZipPlanReasonSymlink        = "symlink skipped"
+)
+
+// ZipPlanFile is a file of the extension source and whether the zip would contain it.
+type ZipPlanFile struct {
+	Path     string `json:"path"`
+	Excluded bool   `json:"excluded"`
+	Reason   string `json:"reason,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// PlanZipFiles lists the files which would be copied into the zip without copying them.
+// With useGit the files of the git archive of the reference are used and the resolved reference is returned.
+func PlanZipFiles(source, reference string, useGit bool, excludes []string) ([]ZipPlanFile, string, error) {
+	var paths []string
+	var symlinks []string
+
+	if useGit {
+		zipReader, resolved, err := gitArchive(source, reference)
+		if err != nil {
+			return nil, "", err
+		}
+
+		reference = resolved
+
+		for _, f := range zipReader.File {
+			if !f.FileInfo().IsDir() {
+				paths = append(paths, f.Name)
+			}
+		}
+	} else {
+		err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(source, path)
+			if err != nil {
+				return err
+			}
+
+			if d.Type()&fs.ModeSymlink != 0 {
+				symlinks = append(symlinks, filepath.ToSlash(relPath))
+			} else {
+				paths = append(paths, filepath.ToSlash(relPath))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("list files: %w", err)
+		}
+	}
+
+	files := make([]ZipPlanFile, 0, len(paths)+len(symlinks))
+
+	for _, symlink := range symlinks {
+		files = append(files, ZipPlanFile{Path: symlink, Excluded: true, Reason: ZipPlanReasonSymlink})
+	}
+
+	for _, path := range paths {
+		files = append(files, planZipFile(path, excludes))
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files, reference, nil
+}
+
+func planZipFile(relPath string, excludes []string) ZipPlanFile {
+	for _, exclude := range excludes {
+		if isPathOrChild(relPath, exclude) {
+			return ZipPlanFile{Path: relPath, Excluded: true, Reason: ZipPlanReasonPackExclude, Detail: fmt.Sprintf("path %s is excluded", exclude)}
+		}
+	}
+
+	if reason := cleanupReason(relPath); reason != "" {
+		return ZipPlanFile{Path: relPath, Excluded: true, Reason: ZipPlanReasonDefaultCleanup, Detail: reason}
+	}
+
+	return ZipPlanFile{Path: relPath}
+}
+
+// minVersionCache keeps the resolved versions, so the hooks and the composer replacements share one lookup.
+var minVersionCache = struct {
+	mu       sync.Mutex
+	versions map[string]string
+}{versions: map[string]string{}}
+
+// LookupMinShopwareVersion returns the lowest core version matching the constraint of the extension.
+// It is used for the composer replacements, which are skipped from 6.6 on.
+func LookupMinShopwareVersion(ctx context.Context, ext Extension) (string, bool, error) {
+	constraint, err := ext.GetShopwareVersionConstraint()
+	if err != nil {
+		return "", false, err
+	}
+
+	minVersionCache.mu.Lock()
+	defer minVersionCache.mu.Unlock()
+
+	minVersion, ok := minVersionCache.versions[constraint.String()]
+	if !ok {
+		if minVersion, err = lookupForMinMatchingVersion(ctx, constraint); err != nil {
+			return "", false, err
+		}
+
+		minVersionCache.versions[constraint.String()] = minVersion
+	}
+
+	shopware66Constraint, _ := version.NewConstraint(">=6.6.0")
+
+	return minVersion, !shopware66Constraint.Check(version.Must(version.NewVersion(minVersion))), nil
+}
+
+// ZipFileName returns the name of the zip created for the extension.
+func ZipFileName(name, tag, outputDir string) string {
+	fileName := fmt.Sprintf("%s-%s.zip", name, tag)
+	if len(tag) == 0 {
+		fileName = fmt.Sprintf("%s.zip", name)
+	}
+
+	if len(outputDir) > 0 {
+		fileName = filepath.Join(outputDir, fileName)
+	}
+
+	return fileName
+}
@@ -0,0 +1,37 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanZipFilesOfFolder(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "src", "Resources", "docs"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "tests"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte("{}"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "src", "Plugin.php"), []byte("<?php"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "src", "Resources", "docs", "readme.md"), []byte(""), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tests", "PluginTest.php"), []byte("<?php"), os.ModePerm))
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "src", "Plugin.php"), filepath.Join(dir, "link.php")))
+
+	files, _, err := PlanZipFiles(dir, "", false, []string{"src/Resources/docs"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []ZipPlanFile{
+		{Path: "composer.json"},
+		{Path: "link.php", Excluded: true, Reason: ZipPlanReasonSymlink},
+		{Path: "src/Plugin.php"},
+		{Path: "src/Resources/docs/readme.md", Excluded: true, Reason: ZipPlanReasonPackExclude, Detail: "path src/Resources/docs is excluded"},
+		{Path: "tests/PluginTest.php", Excluded: true, Reason: ZipPlanReasonDefaultCleanup, Detail: "path tests is not allowed"},
+	}, files)
+}
+
+func TestZipFileName(t *testing.T) {
+	assert.Equal(t, "FroshTools.zip", ZipFileName("FroshTools", "", ""))
+	assert.Equal(t, "build/FroshTools-1.0.0.zip", ZipFileName("FroshTools", "1.0.0", "build"))
+}
@@ -1,7 +1,13 @@
 package extension
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -24,3 +30,43 @@ func TestDetermineMinVersion(t *testing.T) {
 	matchingVersion = getMinMatchingVersion(&constraint, []string{"6.5.0.0-rc1", "abc", "6.4.0.0"})
 	assert.Equal(t, "6.5.0.0-rc1", matchingVersion)
 }
+
+func TestCreateZipIsReproducible(t *testing.T) {
+	source := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(source, "Plugin", "src"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(source, "Plugin", "composer.json"), []byte("{}"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(source, "Plugin", "src", "bin.sh"), []byte("echo"), 0o700))
+
+	modified := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	first := filepath.Join(t.TempDir(), "first.zip")
+	assert.NoError(t, CreateZip(source, first, modified))
+
+	assert.NoError(t, os.Chtimes(filepath.Join(source, "Plugin", "composer.json"), time.Now(), time.Now()))
+
+	second := filepath.Join(t.TempDir(), "second.zip")
+	assert.NoError(t, CreateZip(source, second, modified))
+
+	firstContent, err := os.ReadFile(first)
+	assert.NoError(t, err)
+	secondContent, err := os.ReadFile(second)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(firstContent, secondContent))
+
+	checksumFile, err := WriteChecksumFile(first)
+	assert.NoError(t, err)
+	assert.Equal(t, first+".sha256", checksumFile)
+
+	checksum, err := os.ReadFile(checksumFile)
+	assert.NoError(t, err)
+
+	hash := sha256.Sum256(firstContent)
+	assert.Equal(t, hex.EncodeToString(hash[:])+"  first.zip\n", string(checksum))
+}
+
+func TestZipModifiedTimeUsesSourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), ZipModifiedTime(t.TempDir(), ""))
+
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	assert.Equal(t, zipEpoch, ZipModifiedTime(t.TempDir(), ""))
+}
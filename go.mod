@@ -25,6 +25,7 @@ require (
 	github.com/wI2L/jsondiff v0.6.0
 	github.com/yuin/goldmark v1.7.4
 	go.uber.org/zap v1.27.0
+	golang.org/x/crypto v0.25.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -0,0 +1,219 @@
+package changelog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	AiProviderOpenAI = "openai"
+	// AiProviderStub returns a deterministic summary without any network request, it is meant for tests
+	AiProviderStub = "stub"
+
+	defaultAiTokenEnv = "OPENAI_TOKEN"
+	defaultAiTimeout  = time.Minute
+	defaultAiPrompt   = `{{range .Commits}}{{ .Message }}
+{{end}}Please summarize the changelog into 1-2 sentences and ignore chore or build things. Answer in {{ .LanguageName }}.`
+)
+
+var languageNames = map[string]string{
+	"en-GB": "English",
+	"de-DE": "German",
+	"zh-CN": "Chinese",
+}
+
+// AiConfig configures the provider of the summary, any OpenAI compatible server can be used with base_url.
+type AiConfig struct {
+	Provider string `yaml:"provider,omitempty"`
+	BaseURL  string `yaml:"base_url,omitempty"`
+	Model    string `yaml:"model,omitempty"`
+	// TokenEnv is the environment variable containing the api token
+	TokenEnv string `yaml:"token_env,omitempty"`
+	// Prompt is a go template with .Commits, .Language and .LanguageName
+	Prompt  string        `yaml:"prompt,omitempty"`
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// SummaryRequest is the input for a summary of one language.
+type SummaryRequest struct {
+	Language string
+	Prompt   string
+	Commits  []Commit
+}
+
+type SummaryProvider interface {
+	Summarize(ctx context.Context, request SummaryRequest) (string, error)
+}
+
+type SummaryProviderFactory func(cfg AiConfig) (SummaryProvider, error)
+
+var (
+	summaryProvidersMu sync.RWMutex
+	summaryProviders   = map[string]SummaryProviderFactory{
+		AiProviderOpenAI: newOpenAIProvider,
+		AiProviderStub: func(AiConfig) (SummaryProvider, error) {
+			return stubProvider{}, nil
+		},
+	}
+)
+
+// RegisterSummaryProvider makes a provider available for the provider option of the changelog config.
+func RegisterSummaryProvider(name string, factory SummaryProviderFactory) {
+	summaryProvidersMu.Lock()
+	defer summaryProvidersMu.Unlock()
+
+	summaryProviders[name] = factory
+}
+
+func newSummaryProvider(cfg AiConfig) (SummaryProvider, error) {
+	name := cfg.Provider
+	if name == "" {
+		name = AiProviderOpenAI
+	}
+
+	summaryProvidersMu.RLock()
+	factory, ok := summaryProviders[name]
+	summaryProvidersMu.RUnlock()
+
+	if !ok {
+		names := make([]string, 0, len(summaryProviders))
+		for key := range summaryProviders {
+			names = append(names, key)
+		}
+
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("unknown ai provider %q, available are %s", name, strings.Join(names, ", "))
+	}
+
+	return factory(cfg)
+}
+
+func generateAiMessage(ctx context.Context, changelog []Commit, cfg Config, language string) (string, error) {
+	if !cfg.AiEnabled {
+		return "", nil
+	}
+
+	provider, err := newSummaryProvider(cfg.Ai)
+	if err != nil {
+		return "", err
+	}
+
+	prompt, err := renderAiPrompt(cfg.Ai.Prompt, changelog, language)
+	if err != nil {
+		return "", err
+	}
+
+	timeout := cfg.Ai.Timeout
+	if timeout <= 0 {
+		timeout = defaultAiTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return provider.Summarize(ctx, SummaryRequest{Language: language, Prompt: prompt, Commits: changelog})
+}
+
+func renderAiPrompt(prompt string, changelog []Commit, language string) (string, error) {
+	if prompt == "" {
+		prompt = defaultAiPrompt
+	}
+
+	tpl, err := template.New("prompt").Parse(prompt)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse ai prompt: %w", err)
+	}
+
+	languageName, ok := languageNames[language]
+	if !ok {
+		languageName = language
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, map[string]interface{}{
+		"Commits":      changelog,
+		"Language":     language,
+		"LanguageName": languageName,
+	}); err != nil {
+		return "", fmt.Errorf("cannot render ai prompt: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+type openAIProvider struct {
+	client *openai.Client
+	model  string
+}
+
+func newOpenAIProvider(cfg AiConfig) (SummaryProvider, error) {
+	tokenEnv := cfg.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = defaultAiTokenEnv
+	}
+
+	token := os.Getenv(tokenEnv)
+
+	// self-hosted servers often work without a token
+	if token == "" && cfg.BaseURL == "" {
+		return nil, fmt.Errorf("the environment variable %s is required for the ai summary", tokenEnv)
+	}
+
+	clientConfig := openai.DefaultConfig(token)
+
+	if cfg.BaseURL != "" {
+		clientConfig.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = openai.GPT3Dot5Turbo
+	}
+
+	return openAIProvider{client: openai.NewClientWithConfig(clientConfig), model: model}, nil
+}
+
+func (p openAIProvider) Summarize(ctx context.Context, request SummaryRequest) (string, error) {
+	resp, err := p.client.CreateChatCompletion(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model:  p.model,
+			Stream: false,
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: request.Prompt,
+				},
+			},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.Choices) > 0 {
+		return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+	}
+
+	return "", fmt.Errorf("got no response from %s", p.model)
+}
+
+type stubProvider struct{}
+
+func (stubProvider) Summarize(ctx context.Context, request SummaryRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Summary of %d changes in %s", len(request.Commits), request.Language), nil
+}
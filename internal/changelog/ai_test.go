@@ -0,0 +1,80 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/git"
+)
+
+func TestAiSummaryWithStubProvider(t *testing.T) {
+	commits := []git.GitCommit{
+		{Message: "feat: new module", Hash: "1"},
+		{Message: "fix: typo", Hash: "2"},
+	}
+
+	cfg := Config{
+		AiEnabled: true,
+		Ai:        AiConfig{Provider: AiProviderStub},
+		Template:  "{{ .AiSummarize }}",
+	}
+
+	changelogs, err := renderChangelogs(context.Background(), commits, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "Summary of 2 changes in en-GB", changelogs["en-GB"])
+	assert.Equal(t, "Summary of 2 changes in de-DE", changelogs["de-DE"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = renderChangelogs(ctx, commits, cfg)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	cfg.Ai.Provider = "unknown"
+	_, err = renderChangelogs(context.Background(), commits, cfg)
+	assert.ErrorContains(t, err, "unknown ai provider")
+}
+
+func TestAiSummaryWithCompatibleServer(t *testing.T) {
+	var prompts []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+
+		var request struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "llama", request.Model)
+
+		prompts = append(prompts, request.Messages[0].Content)
+
+		w.Header().Set("content-type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":" Summary "}}]}`))
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		AiEnabled: true,
+		Ai: AiConfig{
+			BaseURL: server.URL + "/v1/",
+			Model:   "llama",
+			Prompt:  "{{ .LanguageName }}:{{range .Commits}} {{ .Description }}{{end}}",
+		},
+		Template: "{{ .AiSummarize }}",
+	}
+
+	changelogs, err := renderChangelogs(context.Background(), []git.GitCommit{{Message: "feat: new module", Hash: "1"}}, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "Summary", changelogs["zh-CN"])
+	assert.Equal(t, []string{"English: new module", "German: new module", "Chinese: new module"}, prompts)
+}
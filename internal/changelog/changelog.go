@@ -5,36 +5,107 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 	"text/template"
 
-	"github.com/sashabaranov/go-openai"
-
 	"github.com/haokeyingxiao/haoke-cli/internal/git"
 )
 
 //go:embed changelog.tpl
 var defaultChangelogTpl string
 
+//go:embed changelog_grouped.tpl
+var groupedChangelogTpl string
+
+const (
+	// SectionBreaking collects the breaking changes of all commit types
+	SectionBreaking = "breaking"
+	// SectionOther collects the commits not following the conventional commit format
+	SectionOther = "other"
+
+	DefaultLanguage = "en-GB"
+)
+
+// Languages are the locales of the changelog files, the store requires all of them.
+var Languages = []string{DefaultLanguage, "de-DE", "zh-CN"}
+
+var defaultSections = []Section{
+	{Types: []string{SectionBreaking}, Titles: map[string]string{"en-GB": "Breaking Changes", "de-DE": "Inkompatible Änderungen", "zh-CN": "不兼容变更"}},
+	{Types: []string{"feat"}, Titles: map[string]string{"en-GB": "Features", "de-DE": "Neue Funktionen", "zh-CN": "新功能"}},
+	{Types: []string{"fix"}, Titles: map[string]string{"en-GB": "Fixes", "de-DE": "Fehlerbehebungen", "zh-CN": "问题修复"}},
+	{Types: []string{SectionOther}, Titles: map[string]string{"en-GB": "Other Changes", "de-DE": "Weitere Änderungen", "zh-CN": "其他变更"}},
+}
+
 type Config struct {
 	Enabled   bool              `yaml:"enabled"`
 	Pattern   string            `yaml:"pattern,omitempty"`
 	Template  string            `yaml:"template,omitempty"`
 	Variables map[string]string `yaml:"variables,omitempty"`
 	AiEnabled bool              `yaml:"ai_enabled,omitempty"`
-	VCSURL    string            `yaml:"-"`
+	Ai        AiConfig          `yaml:"ai,omitempty"`
+	// Grouped renders the conventional commits in sections by their type
+	Grouped  bool      `yaml:"grouped,omitempty"`
+	Sections []Section `yaml:"sections,omitempty"`
+	// PullRequests builds the entries of merged pull requests from the merge commit and reads trailers and issue references
+	PullRequests bool `yaml:"pull_requests,omitempty"`
+	// Trailer overrides the entry of a commit, defaults to Changelog
+	Trailer string `yaml:"trailer,omitempty"`
+	VCSURL  string `yaml:"-"`
+}
+
+// Section of a grouped changelog, commit types which are not part of a section are left out.
+type Section struct {
+	Types []string `yaml:"types"`
+	// Titles by locale, the english title is used for missing locales
+	Titles map[string]string `yaml:"titles"`
+}
+
+func (s Section) Title(language string) string {
+	if title, ok := s.Titles[language]; ok {
+		return title
+	}
+
+	if title, ok := s.Titles[DefaultLanguage]; ok {
+		return title
+	}
+
+	return strings.Join(s.Types, ", ")
 }
 
 type Commit struct {
-	Message   string
-	Hash      string
-	Variables map[string]string
+	Message     string
+	Hash        string
+	Variables   map[string]string
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+	Author      string
+	// PullRequest is the number of the pull request or merge request, only set with the pull_requests option
+	PullRequest    string
+	PullRequestURL string
+	Issues         []Issue
+	Trailers       map[string]string
+}
+
+type RenderedSection struct {
+	Title   string
+	Commits []Commit
 }
 
-// GenerateChangelog generates a changelog from the git repository.
+// GenerateChangelog generates the english changelog from the git repository.
 func GenerateChangelog(ctx context.Context, repository string, cfg Config) (string, error) {
+	changelogs, err := GenerateChangelogs(ctx, repository, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return changelogs[DefaultLanguage], nil
+}
+
+// GenerateChangelogs generates the changelog of every language from the git repository.
+func GenerateChangelogs(ctx context.Context, repository string, cfg Config) (map[string]string, error) {
 	var err error
 
 	if strings.Contains(cfg.Template, "Config.VCSURL") {
@@ -42,20 +113,49 @@ func GenerateChangelog(ctx context.Context, repository string, cfg Config) (stri
 	}
 
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	if cfg.PullRequests && cfg.VCSURL == "" {
+		// the links of pull requests and issues are optional
+		cfg.VCSURL, _ = git.GetPublicVCSURL(ctx, repository)
+	}
+
+	var commits []git.GitCommit
+
+	if cfg.PullRequests {
+		commits, err = git.GetCommitsWithMerges(ctx, repository)
+	} else {
+		commits, err = git.GetCommits(ctx, repository)
 	}
 
-	commits, err := git.GetCommits(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderChangelogs(ctx, commits, cfg)
+}
+
+func renderChangelog(ctx context.Context, commits []git.GitCommit, cfg Config) (string, error) {
+	changelogs, err := renderChangelogs(ctx, commits, cfg)
 	if err != nil {
 		return "", err
 	}
 
-	return renderChangelog(commits, cfg)
+	return changelogs[DefaultLanguage], nil
 }
 
-func renderChangelog(commits []git.GitCommit, cfg Config) (string, error) {
+func renderChangelogs(ctx context.Context, commits []git.GitCommit, cfg Config) (map[string]string, error) {
 	if cfg.Template == "" {
 		cfg.Template = defaultChangelogTpl
+
+		if cfg.Grouped {
+			cfg.Template = groupedChangelogTpl
+		}
+	}
+
+	if len(cfg.Sections) == 0 {
+		cfg.Sections = defaultSections
 	}
 
 	var matcher *regexp.Regexp
@@ -68,16 +168,53 @@ func renderChangelog(commits []git.GitCommit, cfg Config) (string, error) {
 		variableMatchers[key] = regexp.MustCompile(value)
 	}
 
+	links := newVCSLinks(cfg.VCSURL)
+
 	changelog := make([]Commit, 0)
 	for _, commit := range commits {
+		var meta commitMetadata
+
+		original := commit
+
+		if cfg.PullRequests {
+			meta = parseCommitMetadata(commit, cfg.Trailer, links)
+			if meta.Skip {
+				continue
+			}
+
+			commit.Message = meta.Message
+		}
+
 		if matcher != nil && !matcher.MatchString(commit.Message) {
 			continue
 		}
 
 		parsed := Commit{
-			Message:   commit.Message,
-			Hash:      commit.Hash,
-			Variables: make(map[string]string),
+			Message:        commit.Message,
+			Hash:           commit.Hash,
+			Variables:      make(map[string]string),
+			Description:    commit.Message,
+			Author:         commit.Author,
+			PullRequest:    meta.PullRequest,
+			PullRequestURL: meta.PullRequestURL,
+			Issues:         meta.Issues,
+			Trailers:       meta.Trailers,
+		}
+
+		conventional, ok := ParseConventionalCommit(commit)
+
+		// a changelog trailer or pull request title without a type only replaces the description of the original subject
+		if !ok && commit.Message != original.Message {
+			if conventional, ok = ParseConventionalCommit(original); ok {
+				conventional.Description = commit.Message
+			}
+		}
+
+		if ok {
+			parsed.Type = conventional.Type
+			parsed.Scope = conventional.Scope
+			parsed.Breaking = conventional.Breaking
+			parsed.Description = conventional.Description
 		}
 
 		for key, variableMatcher := range variableMatchers {
@@ -94,59 +231,72 @@ func renderChangelog(commits []git.GitCommit, cfg Config) (string, error) {
 
 	templateParsed := template.Must(template.New("changelog").Parse(cfg.Template))
 
-	aiMessage, err := generateAiMessage(changelog, cfg)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate AI message: %v", err)
-	}
+	changelogs := make(map[string]string, len(Languages))
 
-	templateContext := map[string]interface{}{
-		"Commits":     changelog,
-		"Config":      cfg,
-		"AiSummarize": aiMessage,
-	}
+	for _, language := range Languages {
+		aiMessage, err := generateAiMessage(ctx, changelog, cfg, language)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate AI message: %w", err)
+		}
 
-	var buf bytes.Buffer
-	if err := templateParsed.Execute(&buf, templateContext); err != nil {
-		return "", fmt.Errorf("failed to execute template: %v", err)
+		templateContext := map[string]interface{}{
+			"Commits":     changelog,
+			"Sections":    groupCommits(changelog, cfg.Sections, language),
+			"Language":    language,
+			"Config":      cfg,
+			"AiSummarize": aiMessage,
+		}
+
+		var buf bytes.Buffer
+		if err := templateParsed.Execute(&buf, templateContext); err != nil {
+			return nil, fmt.Errorf("failed to execute template: %v", err)
+		}
+
+		changelogs[language] = strings.Trim(buf.String(), "\n")
 	}
 
-	return strings.Trim(buf.String(), "\n"), nil
+	return changelogs, nil
 }
 
-func generateAiMessage(changelog []Commit, cfg Config) (string, error) {
-	if !cfg.AiEnabled {
-		return "", nil
-	}
+// groupCommits assigns every commit to the first matching section, empty sections are left out.
+func groupCommits(commits []Commit, sections []Section, language string) []RenderedSection {
+	rendered := make([]RenderedSection, len(sections))
 
-	aiRequestBody := ""
+	for i, section := range sections {
+		rendered[i].Title = section.Title(language)
+	}
 
-	for _, commit := range changelog {
-		aiRequestBody += commit.Message + "\n"
+	for _, commit := range commits {
+		for i, section := range sections {
+			if sectionContains(section, commit) {
+				rendered[i].Commits = append(rendered[i].Commits, commit)
+				break
+			}
+		}
 	}
 
-	aiRequestBody += "Please summarize the changelog into 1-2 sentences and ignore chore or build things"
+	result := make([]RenderedSection, 0, len(rendered))
 
-	client := openai.NewClient(os.Getenv("OPENAI_TOKEN"))
-	resp, err := client.CreateChatCompletion(
-		context.Background(),
-		openai.ChatCompletionRequest{
-			Model:  openai.GPT3Dot5Turbo,
-			Stream: false,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: aiRequestBody,
-				},
-			},
-		},
-	)
-	if err != nil {
-		return "", err
+	for _, section := range rendered {
+		if len(section.Commits) > 0 {
+			result = append(result, section)
+		}
 	}
 
-	if len(resp.Choices) > 0 {
-		return resp.Choices[0].Message.Content, nil
+	return result
+}
+
+func sectionContains(section Section, commit Commit) bool {
+	for _, sectionType := range section.Types {
+		switch {
+		case sectionType == SectionBreaking && commit.Breaking:
+			return true
+		case sectionType == SectionOther && commit.Type == "":
+			return true
+		case sectionType == commit.Type && commit.Type != "":
+			return true
+		}
 	}
 
-	return "", fmt.Errorf("got no response from openai: %w", err)
+	return false
 }
@@ -3,5 +3,5 @@
 
 Commits:
 {{end}}
-{{range .Commits}}- [{{ .Message }}]({{ $.Config.VCSURL }}/{{ .Hash }})
+{{range .Commits}}- [{{ .Message }}]({{ $.Config.VCSURL }}/{{ .Hash }}){{if .PullRequestURL}} ([#{{ .PullRequest }}]({{ .PullRequestURL }})){{end}}
 {{end}}
\ No newline at end of file
@@ -0,0 +1,9 @@
+{{if .AiSummarize }}
+{{ .AiSummarize }}
+
+{{end}}
+{{range .Sections}}**{{ .Title }}**
+
+{{range .Commits}}- {{if .Scope}}**{{ .Scope }}:** {{end}}[{{ .Description }}]({{ $.Config.VCSURL }}/{{ .Hash }}){{if .PullRequestURL}} ([#{{ .PullRequest }}]({{ .PullRequestURL }})){{end}}
+{{end}}
+{{end}}
@@ -1,6 +1,7 @@
 package changelog
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -16,7 +17,7 @@ func TestGenerateWithoutConfig(t *testing.T) {
 		},
 	}
 
-	changelog, err := renderChangelog(commits, Config{VCSURL: "https://github.com/FriendsOfShopware/FroshTools/commit"})
+	changelog, err := renderChangelog(context.Background(), commits, Config{VCSURL: "https://github.com/FriendsOfShopware/FroshTools/commit"})
 
 	assert.NoError(t, err)
 
@@ -38,7 +39,7 @@ func TestTicketParsing(t *testing.T) {
 		Template: "{{range .Commits}}- [{{ .Message }}](https://issues.haokeyingxiao.com/issues/{{ .Variables.ticket }}){{end}}",
 	}
 
-	changelog, err := renderChangelog(commits, cfg)
+	changelog, err := renderChangelog(context.Background(), commits, cfg)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "- [NEXT-1234 - Fooo](https://issues.haokeyingxiao.com/issues/NEXT-1234)", changelog)
@@ -60,7 +61,7 @@ func TestIncludeFilters(t *testing.T) {
 		Pattern: "^(NEXT-[0-9]+)",
 	}
 
-	changelog, err := renderChangelog(commits, cfg)
+	changelog, err := renderChangelog(context.Background(), commits, cfg)
 
 	assert.NoError(t, err)
 	assert.Equal(t, changelog, "- [NEXT-1234 - Fooo](/1234567890)")
@@ -90,8 +91,76 @@ func TestLetAiGenerateText(t *testing.T) {
 		AiEnabled: true,
 	}
 
-	changelog, err := renderChangelog(commits, cfg)
+	changelog, err := renderChangelog(context.Background(), commits, cfg)
 
 	assert.NoError(t, err)
 	assert.Contains(t, changelog, "Commits:")
 }
+
+func TestGroupedChangelog(t *testing.T) {
+	commits := []git.GitCommit{
+		{Message: "feat(admin): new module", Hash: "1"},
+		{Message: "fix: typo", Hash: "2"},
+		{Message: "chore: update deps", Hash: "3"},
+		{Message: "refactor!: drop old api", Hash: "4"},
+		{Message: "Merge something", Hash: "5"},
+	}
+
+	changelogs, err := renderChangelogs(context.Background(), commits, Config{Grouped: true, VCSURL: "https://example.com/commit"})
+	assert.NoError(t, err)
+	assert.Len(t, changelogs, len(Languages))
+
+	assert.Equal(t, `**Breaking Changes**
+
+- [drop old api](https://example.com/commit/4)
+
+**Features**
+
+- **admin:** [new module](https://example.com/commit/1)
+
+**Fixes**
+
+- [typo](https://example.com/commit/2)
+
+**Other Changes**
+
+- [Merge something](https://example.com/commit/5)`, changelogs["en-GB"])
+
+	assert.Contains(t, changelogs["de-DE"], "**Neue Funktionen**")
+	assert.Contains(t, changelogs["zh-CN"], "**新功能**")
+}
+
+func TestGroupedChangelogCustomSections(t *testing.T) {
+	commits := []git.GitCommit{
+		{Message: "feat: new module", Hash: "1"},
+		{Message: "perf: faster", Hash: "2"},
+		{Message: "fix: typo", Hash: "3"},
+	}
+
+	cfg := Config{
+		Grouped: true,
+		Sections: []Section{
+			{Types: []string{"feat", "perf"}, Titles: map[string]string{"en-GB": "Improvements", "de-DE": "Verbesserungen"}},
+		},
+		Template: "{{range .Sections}}{{ .Title }}:{{range .Commits}} {{ .Description }}{{end}}{{end}}",
+	}
+
+	changelogs, err := renderChangelogs(context.Background(), commits, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "Improvements: new module faster", changelogs["en-GB"])
+	assert.Equal(t, "Verbesserungen: new module faster", changelogs["de-DE"])
+	assert.Equal(t, "Improvements: new module faster", changelogs["zh-CN"])
+}
+
+func TestParseConventionalCommit(t *testing.T) {
+	parsed, ok := ParseConventionalCommit(git.GitCommit{Message: "fix(cart)!: rounding", Body: "details"})
+	assert.True(t, ok)
+	assert.Equal(t, ConventionalCommit{Type: "fix", Scope: "cart", Breaking: true, Description: "rounding"}, parsed)
+
+	parsed, ok = ParseConventionalCommit(git.GitCommit{Message: "feat: config", Body: "BREAKING CHANGE: renamed keys"})
+	assert.True(t, ok)
+	assert.True(t, parsed.Breaking)
+
+	_, ok = ParseConventionalCommit(git.GitCommit{Message: "NEXT-1234 - Fooo"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,41 @@
+package changelog
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/git"
+)
+
+var conventionalCommitPattern = regexp.MustCompile(`^(?P<type>[a-zA-Z]+)(?:\((?P<scope>[^)]*)\))?(?P<breaking>!)?:\s*(?P<description>.+)$`)
+
+// ConventionalCommit is a commit following https://www.conventionalcommits.org.
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// ParseConventionalCommit returns false when the commit does not follow the conventional commit format.
+func ParseConventionalCommit(commit git.GitCommit) (ConventionalCommit, bool) {
+	matches := conventionalCommitPattern.FindStringSubmatch(strings.TrimSpace(commit.Message))
+	if matches == nil {
+		return ConventionalCommit{}, false
+	}
+
+	parsed := ConventionalCommit{
+		Type:        strings.ToLower(matches[conventionalCommitPattern.SubexpIndex("type")]),
+		Scope:       matches[conventionalCommitPattern.SubexpIndex("scope")],
+		Breaking:    matches[conventionalCommitPattern.SubexpIndex("breaking")] != "",
+		Description: matches[conventionalCommitPattern.SubexpIndex("description")],
+	}
+
+	for _, line := range strings.Split(commit.Body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			parsed.Breaking = true
+		}
+	}
+
+	return parsed, true
+}
@@ -0,0 +1,159 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/git"
+)
+
+const defaultTrailer = "Changelog"
+
+var (
+	trailerPattern          = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*):\s*(.+)$`)
+	githubMergePattern      = regexp.MustCompile(`^Merge pull request #(\d+) from `)
+	githubSquashPattern     = regexp.MustCompile(`\s*\(#(\d+)\)$`)
+	gitlabMergePattern      = regexp.MustCompile(`(?m)^See merge request \S+!(\d+)$`)
+	gitlabMergeTitlePattern = regexp.MustCompile(`^Merge branch '.+' into '.+'$`)
+	issueReferencePattern   = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?|refs?|issue)\s*:?\s+#(\d+)`)
+)
+
+// Issue is an issue referenced by the commit message.
+type Issue struct {
+	Number string
+	URL    string
+}
+
+type commitMetadata struct {
+	Message        string
+	PullRequest    string
+	PullRequestURL string
+	Issues         []Issue
+	Trailers       map[string]string
+	Skip           bool
+}
+
+// vcsLinks builds the urls of pull requests and issues based on the commit url of GetPublicVCSURL.
+type vcsLinks struct {
+	repository string
+	gitlab     bool
+}
+
+func newVCSLinks(commitURL string) vcsLinks {
+	if strings.HasSuffix(commitURL, "/-/commit") {
+		return vcsLinks{repository: strings.TrimSuffix(commitURL, "/-/commit"), gitlab: true}
+	}
+
+	return vcsLinks{repository: strings.TrimSuffix(commitURL, "/commit")}
+}
+
+func (l vcsLinks) pullRequest(number string) string {
+	if l.repository == "" || number == "" {
+		return ""
+	}
+
+	if l.gitlab {
+		return fmt.Sprintf("%s/-/merge_requests/%s", l.repository, number)
+	}
+
+	return fmt.Sprintf("%s/pull/%s", l.repository, number)
+}
+
+func (l vcsLinks) issue(number string) string {
+	if l.repository == "" {
+		return ""
+	}
+
+	if l.gitlab {
+		return fmt.Sprintf("%s/-/issues/%s", l.repository, number)
+	}
+
+	return fmt.Sprintf("%s/issues/%s", l.repository, number)
+}
+
+// parseCommitMetadata resolves the changelog entry of the commit: the trailer wins over the pull request title and the subject.
+// A trailer with the value skip or - leaves the commit out of the changelog.
+func parseCommitMetadata(commit git.GitCommit, trailer string, links vcsLinks) commitMetadata {
+	if trailer == "" {
+		trailer = defaultTrailer
+	}
+
+	meta := commitMetadata{
+		Message:  commit.Message,
+		Trailers: parseTrailers(commit.Body),
+	}
+
+	switch {
+	case githubMergePattern.MatchString(commit.Message):
+		meta.PullRequest = githubMergePattern.FindStringSubmatch(commit.Message)[1]
+		meta.Message = firstBodyLine(commit.Body, commit.Message)
+	case gitlabMergePattern.MatchString(commit.Body):
+		meta.PullRequest = gitlabMergePattern.FindStringSubmatch(commit.Body)[1]
+
+		if gitlabMergeTitlePattern.MatchString(commit.Message) {
+			meta.Message = firstBodyLine(commit.Body, commit.Message)
+		}
+	case githubSquashPattern.MatchString(commit.Message):
+		meta.PullRequest = githubSquashPattern.FindStringSubmatch(commit.Message)[1]
+		meta.Message = githubSquashPattern.ReplaceAllString(commit.Message, "")
+	}
+
+	meta.PullRequestURL = links.pullRequest(meta.PullRequest)
+
+	for key, value := range meta.Trailers {
+		if !strings.EqualFold(key, trailer) {
+			continue
+		}
+
+		if value == "-" || strings.EqualFold(value, "skip") {
+			meta.Skip = true
+		}
+
+		meta.Message = value
+	}
+
+	seen := map[string]bool{}
+
+	for _, match := range issueReferencePattern.FindAllStringSubmatch(commit.Message+"\n"+commit.Body, -1) {
+		if seen[match[1]] {
+			continue
+		}
+
+		seen[match[1]] = true
+		meta.Issues = append(meta.Issues, Issue{Number: match[1], URL: links.issue(match[1])})
+	}
+
+	return meta
+}
+
+// parseTrailers reads the "Key: value" lines of the last paragraph of the body.
+func parseTrailers(body string) map[string]string {
+	trailers := map[string]string{}
+
+	paragraphs := strings.Split(strings.TrimSpace(body), "\n\n")
+	lines := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+
+	for _, line := range lines {
+		matches := trailerPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			return map[string]string{}
+		}
+
+		if _, ok := trailers[matches[1]]; !ok {
+			trailers[matches[1]] = matches[2]
+		}
+	}
+
+	return trailers
+}
+
+func firstBodyLine(body, fallback string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+
+	return fallback
+}
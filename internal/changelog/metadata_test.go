@@ -0,0 +1,70 @@
+package changelog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/git"
+)
+
+func TestPullRequestMetadata(t *testing.T) {
+	commits := []git.GitCommit{
+		{Message: "Merge pull request #12 from frosh/feature", Body: "feat: new module\n\nFixes #3", Hash: "1", Author: "Jane", Merge: true},
+		{Message: "fix: typo (#13)", Body: "Long description\n\nChangelog: Fixed a typo in the settings\nRefs: #4", Hash: "2", Author: "John"},
+		{Message: "chore: bump deps (#14)", Body: "Changelog: skip", Hash: "3"},
+		{Message: "direct commit", Hash: "4"},
+	}
+
+	cfg := Config{
+		PullRequests: true,
+		VCSURL:       "https://github.com/FriendsOfShopware/FroshTools/commit",
+		Template:     "{{range .Commits}}{{ .Message }}|{{ .Type }}|{{ .Author }}|{{ .PullRequestURL }}|{{range .Issues}}{{ .URL }}{{end}}\n{{end}}",
+	}
+
+	changelog, err := renderChangelog(context.Background(), commits, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, `feat: new module|feat|Jane|https://github.com/FriendsOfShopware/FroshTools/pull/12|https://github.com/FriendsOfShopware/FroshTools/issues/3
+Fixed a typo in the settings|fix|John|https://github.com/FriendsOfShopware/FroshTools/pull/13|https://github.com/FriendsOfShopware/FroshTools/issues/4
+direct commit||||`, changelog)
+}
+
+func TestPullRequestMetadataKeepsConventionalType(t *testing.T) {
+	commits := []git.GitCommit{
+		{Message: "fix(admin): typo (#13)", Body: "Changelog: Fixed a typo in the settings", Hash: "1"},
+		{Message: "feat: new module (#14)", Hash: "2"},
+	}
+
+	cfg := Config{
+		PullRequests: true,
+		Grouped:      true,
+	}
+
+	changelogs, err := renderChangelogs(context.Background(), commits, cfg)
+	assert.NoError(t, err)
+	assert.NotContains(t, changelogs["en-GB"], "Other Changes")
+	assert.Contains(t, changelogs["en-GB"], "**Fixes**")
+	assert.Contains(t, changelogs["en-GB"], "Fixed a typo in the settings")
+	assert.Contains(t, changelogs["en-GB"], "new module")
+}
+
+func TestGitlabMergeRequestMetadata(t *testing.T) {
+	links := newVCSLinks("https://gitlab.com/group/project/-/commit")
+
+	meta := parseCommitMetadata(git.GitCommit{
+		Message: "Merge branch 'feature' into 'main'",
+		Body:    "Add new module\n\nCloses #7\n\nSee merge request group/project!21",
+	}, "", links)
+
+	assert.Equal(t, "Add new module", meta.Message)
+	assert.Equal(t, "21", meta.PullRequest)
+	assert.Equal(t, "https://gitlab.com/group/project/-/merge_requests/21", meta.PullRequestURL)
+	assert.Equal(t, []Issue{{Number: "7", URL: "https://gitlab.com/group/project/-/issues/7"}}, meta.Issues)
+}
+
+func TestParseTrailers(t *testing.T) {
+	assert.Equal(t, map[string]string{"Changelog": "Better", "Signed-off-by": "Jane"}, parseTrailers("Text\n\nChangelog: Better\nSigned-off-by: Jane"))
+	assert.Empty(t, parseTrailers("Text\n\nnot a trailer\nChangelog: Better"))
+	assert.Empty(t, parseTrailers(""))
+}
@@ -1,18 +1,26 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"github.com/caarlos0/env/v9"
+	"github.com/haokeyingxiao/haoke-cli/internal/secret"
 	"gopkg.in/yaml.v3"
 	"os"
+	"sort"
 	"sync"
 )
 
 var (
 	state                        *configState
 	environmentConfigErrorFormat = "could not set config value %s to %q config was loaded from the environment variables"
+	// secretStore keeps the account passwords out of the config file
+	secretStore = secret.Default
 )
 
+// DefaultAccountProfile is the name of the account configured in the account section.
+const DefaultAccountProfile = "default"
+
 type configState struct {
 	mu            sync.RWMutex
 	cfgPath       string
@@ -20,13 +28,21 @@ type configState struct {
 	loadedFromEnv bool
 	isReady       bool
 	modified      bool
+	// profile overrides the current account of the config for this process
+	profile string
+}
+
+type configAccount struct {
+	Email string `env:"HAOKE_CLI_ACCOUNT_EMAIL" yaml:"email"`
+	// Password is only set by the environment or by config files written before the secret store
+	Password string `env:"HAOKE_CLI_ACCOUNT_PASSWORD" yaml:"password,omitempty"`
+	Company  string `env:"HAOKE_CLI_ACCOUNT_COMPANY" yaml:"company,omitempty"`
 }
 
 type configData struct {
-	Account struct {
-		Email    string `env:"HAOKE_CLI_ACCOUNT_EMAIL" yaml:"email"`
-		Password string `env:"HAOKE_CLI_ACCOUNT_PASSWORD" yaml:"password"`
-	} `yaml:"account"`
+	Account        configAccount             `yaml:"account"`
+	Accounts       map[string]*configAccount `yaml:"accounts,omitempty"`
+	CurrentAccount string                    `yaml:"current_account,omitempty"`
 }
 
 type ExtensionConfig struct {
@@ -143,16 +159,125 @@ func createNewConfig(path string) error {
 	return f.Close()
 }
 
+// UseAccountProfile selects the account profile for this process without changing the current account of the config.
+func UseAccountProfile(name string) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.profile = name
+}
+
+func (s *configState) profileName() string {
+	if s.loadedFromEnv {
+		return DefaultAccountProfile
+	}
+
+	if s.profile != "" {
+		return s.profile
+	}
+
+	if s.inner.CurrentAccount != "" {
+		return s.inner.CurrentAccount
+	}
+
+	return DefaultAccountProfile
+}
+
+// account returns the active account, with create a missing profile is added to the config.
+func (s *configState) account(create bool) *configAccount {
+	name := s.profileName()
+
+	if name == DefaultAccountProfile {
+		return &s.inner.Account
+	}
+
+	if account, ok := s.inner.Accounts[name]; ok {
+		return account
+	}
+
+	if !create {
+		return &configAccount{}
+	}
+
+	if s.inner.Accounts == nil {
+		s.inner.Accounts = make(map[string]*configAccount)
+	}
+
+	s.inner.Accounts[name] = &configAccount{}
+
+	return s.inner.Accounts[name]
+}
+
+func (Config) GetAccountProfile() string {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return state.profileName()
+}
+
+// GetAccountProfiles returns the names of all configured account profiles.
+func (Config) GetAccountProfiles() []string {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	profiles := make([]string, 0, len(state.inner.Accounts)+1)
+
+	if state.inner.Account.Email != "" {
+		profiles = append(profiles, DefaultAccountProfile)
+	}
+
+	for name := range state.inner.Accounts {
+		profiles = append(profiles, name)
+	}
+
+	sort.Strings(profiles)
+
+	return profiles
+}
+
+// GetAccountProfileDetails returns the email and company of the named account profile.
+func (Config) GetAccountProfileDetails(name string) (string, string) {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+
+	account := &state.inner.Account
+
+	if name != DefaultAccountProfile {
+		var ok bool
+		if account, ok = state.inner.Accounts[name]; !ok {
+			return "", ""
+		}
+	}
+
+	return account.Email, account.Company
+}
+
 func (Config) GetAccountEmail() string {
 	state.mu.RLock()
 	defer state.mu.RUnlock()
-	return state.inner.Account.Email
+	return state.account(false).Email
 }
 
 func (Config) GetAccountPassword() string {
 	state.mu.RLock()
 	defer state.mu.RUnlock()
-	return state.inner.Account.Password
+
+	if password := state.account(false).Password; password != "" {
+		return password
+	}
+
+	store, err := secretStore()
+	if err != nil {
+		return ""
+	}
+
+	password, _ := store.Get(passwordSecretKey(state.profileName()))
+
+	return password
+}
+
+func (Config) GetAccountCompany() string {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return state.account(false).Company
 }
 
 func (Config) SetAccountEmail(email string) error {
@@ -162,7 +287,7 @@ func (Config) SetAccountEmail(email string) error {
 		return fmt.Errorf(environmentConfigErrorFormat, "account.email", email)
 	}
 	state.modified = true
-	state.inner.Account.Email = email
+	state.account(true).Email = email
 	return nil
 }
 
@@ -172,8 +297,83 @@ func (Config) SetAccountPassword(password string) error {
 	if state.loadedFromEnv {
 		return fmt.Errorf(environmentConfigErrorFormat, "account.password", "***")
 	}
+
+	store, err := secretStore()
+	if err != nil {
+		return err
+	}
+
+	key := passwordSecretKey(state.profileName())
+
+	if password == "" {
+		err = store.Delete(key)
+	} else {
+		err = store.Set(key, password)
+	}
+
+	if err != nil && !errors.Is(err, secret.ErrNotFound) {
+		return fmt.Errorf("cannot store password in %s: %w", store.Name(), err)
+	}
+
+	// a password of an older config file is removed on the next save
 	state.modified = true
-	state.inner.Account.Password = password
+	state.account(true).Password = ""
+	return nil
+}
+
+// MigrateAccountPassword moves the password of an older config file into the secret store and removes it from the file.
+func (c Config) MigrateAccountPassword() error {
+	state.mu.RLock()
+	password := ""
+	if !state.loadedFromEnv {
+		password = state.account(false).Password
+	}
+	state.mu.RUnlock()
+
+	if password == "" {
+		return nil
+	}
+
+	if err := c.SetAccountPassword(password); err != nil {
+		return err
+	}
+
+	return c.Save()
+}
+
+func passwordSecretKey(profile string) string {
+	return fmt.Sprintf("account-password-%s", profile)
+}
+
+func (Config) SetAccountCompany(company string) error {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.loadedFromEnv {
+		return fmt.Errorf(environmentConfigErrorFormat, "account.company", company)
+	}
+	state.modified = true
+	state.account(true).Company = company
+	return nil
+}
+
+// SetCurrentAccountProfile changes the account profile which is used by default.
+func (Config) SetCurrentAccountProfile(name string) error {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.loadedFromEnv {
+		return fmt.Errorf(environmentConfigErrorFormat, "current_account", name)
+	}
+
+	if name != DefaultAccountProfile {
+		if _, ok := state.inner.Accounts[name]; !ok {
+			return fmt.Errorf("account profile %s does not exist", name)
+		}
+	}
+
+	state.modified = true
+	state.inner.CurrentAccount = name
+	state.profile = ""
+
 	return nil
 }
 
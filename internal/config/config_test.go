@@ -9,6 +9,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/secret"
 )
 
 func TestParseEnvConfig(t *testing.T) {
@@ -62,6 +64,7 @@ func TestParseFileConfig(t *testing.T) {
 
 func TestSaveConfig(t *testing.T) {
 	defer resetState()
+	useTestSecretStore(t)
 
 	testData := struct {
 		email, password string
@@ -102,7 +105,11 @@ func TestSaveConfig(t *testing.T) {
 	assert.NoError(t, yaml.Unmarshal(newConfData, &newConf))
 
 	assert.Equal(t, testData.email, newConf.Account.Email)
-	assert.Equal(t, testData.password, newConf.Account.Password)
+	assert.Empty(t, newConf.Account.Password)
+	assert.Equal(t, testData.password, configService.GetAccountPassword())
+
+	assert.NoError(t, configService.SetAccountPassword(""))
+	assert.Empty(t, configService.GetAccountPassword())
 }
 
 func TestDontWriteEnvConfig(t *testing.T) {
@@ -129,6 +136,91 @@ func TestDontWriteEnvConfig(t *testing.T) {
 	assert.Error(t, confService.SetAccountPassword("S3CR3TF4RT3St"))
 }
 
+func TestAccountProfiles(t *testing.T) {
+	defer resetState()
+	useTestSecretStore(t)
+
+	testConfig := path.Join(t.TempDir(), "config.yml")
+	assert.NoError(t, os.WriteFile(testConfig, []byte("account:\n  email: default@test.com\n  password: default\naccounts:\n  agency:\n    email: agency@test.com\n    password: agency\n    company: \"42\"\n"), os.ModePerm))
+
+	assert.NoError(t, InitConfig(testConfig))
+
+	confService := Config{}
+	assert.Equal(t, []string{"agency", DefaultAccountProfile}, confService.GetAccountProfiles())
+	assert.Equal(t, DefaultAccountProfile, confService.GetAccountProfile())
+	assert.Equal(t, "default@test.com", confService.GetAccountEmail())
+
+	UseAccountProfile("agency")
+	assert.Equal(t, "agency", confService.GetAccountProfile())
+	assert.Equal(t, "agency@test.com", confService.GetAccountEmail())
+	assert.Equal(t, "42", confService.GetAccountCompany())
+
+	UseAccountProfile("new")
+	assert.Equal(t, "", confService.GetAccountEmail())
+	assert.NoError(t, confService.SetAccountEmail("new@test.com"))
+	assert.NoError(t, confService.SetAccountPassword("new"))
+	assert.Equal(t, "new", confService.GetAccountPassword())
+	assert.NoError(t, confService.SetCurrentAccountProfile("new"))
+	assert.Error(t, confService.SetCurrentAccountProfile("missing"))
+	assert.NoError(t, SaveConfig())
+
+	newConfData, err := os.ReadFile(testConfig)
+	assert.NoError(t, err)
+
+	var newConf configData
+	assert.NoError(t, yaml.Unmarshal(newConfData, &newConf))
+
+	assert.Equal(t, "new", newConf.CurrentAccount)
+	assert.Equal(t, "new@test.com", newConf.Accounts["new"].Email)
+	assert.Equal(t, "default@test.com", newConf.Account.Email)
+}
+
+func TestMigrateLegacyAccountPassword(t *testing.T) {
+	defer resetState()
+	useTestSecretStore(t)
+
+	testConfig := path.Join(t.TempDir(), "config.yml")
+	assert.NoError(t, os.WriteFile(testConfig, []byte("account:\n  email: legacy@test.com\n  password: legacy\n"), os.ModePerm))
+
+	assert.NoError(t, InitConfig(testConfig))
+
+	confService := Config{}
+	assert.NoError(t, confService.MigrateAccountPassword())
+	assert.Equal(t, "legacy", confService.GetAccountPassword())
+
+	newConfData, err := os.ReadFile(testConfig)
+	assert.NoError(t, err)
+
+	var newConf configData
+	assert.NoError(t, yaml.Unmarshal(newConfData, &newConf))
+
+	assert.Equal(t, "legacy@test.com", newConf.Account.Email)
+	assert.Empty(t, newConf.Account.Password)
+
+	store, err := secretStore()
+	assert.NoError(t, err)
+
+	stored, err := store.Get(passwordSecretKey(DefaultAccountProfile))
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy", stored)
+
+	// nothing is left to migrate
+	assert.NoError(t, confService.MigrateAccountPassword())
+}
+
+func useTestSecretStore(t *testing.T) {
+	t.Helper()
+
+	store := secret.NewFileStore(path.Join(t.TempDir(), "secrets.json"), "")
+	secretStore = func() (secret.Store, error) {
+		return store, nil
+	}
+
+	t.Cleanup(func() {
+		secretStore = secret.Default
+	})
+}
+
 func resetState() {
 	state = &configState{
 		mu:      sync.RWMutex{},
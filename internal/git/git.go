@@ -12,6 +12,10 @@ import (
 type GitCommit struct {
 	Hash    string
 	Message string
+	// Body is the commit message without the subject line
+	Body   string
+	Author string
+	Merge  bool
 }
 
 func runGit(ctx context.Context, repo string, args ...string) (string, error) {
@@ -70,7 +74,18 @@ func getPreviousTag(ctx context.Context, repo string) (string, error) {
 	return commitsArray[len(commitsArray)-1], nil
 }
 
+// GetCommits returns the commits since the previous tag without merge commits.
 func GetCommits(ctx context.Context, repo string) ([]GitCommit, error) {
+	return getCommitsSincePreviousTag(ctx, repo, "--no-merges")
+}
+
+// GetCommitsWithMerges returns the commits of the current branch since the previous tag including merge commits.
+// The commits of merged branches are left out, as they are described by the merge commit.
+func GetCommitsWithMerges(ctx context.Context, repo string) ([]GitCommit, error) {
+	return getCommitsSincePreviousTag(ctx, repo, "--first-parent")
+}
+
+func getCommitsSincePreviousTag(ctx context.Context, repo, filter string) ([]GitCommit, error) {
 	if err := unshallowRepository(ctx, repo); err != nil {
 		return nil, err
 	}
@@ -80,29 +95,55 @@ func GetCommits(ctx context.Context, repo string) ([]GitCommit, error) {
 		return nil, err
 	}
 
-	commits, err := runGit(ctx, repo, "log", "--pretty=format:%h|%s", previousTag+"..HEAD", "--no-merges")
+	// fields are separated by the unit separator and commits by the record separator, as bodies contain new lines
+	commits, err := runGit(ctx, repo, "log", "--pretty=format:%h%x1f%s%x1f%an%x1f%p%x1f%b%x1e", previousTag+"..HEAD", filter)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get commits: %w", err)
 	}
 
-	if commits == "" {
-		return []GitCommit{}, nil
-	}
+	gitCommits := make([]GitCommit, 0)
 
-	commitsArray := strings.Split(commits, "\n")
-	gitCommits := make([]GitCommit, len(commitsArray))
+	for _, record := range strings.Split(commits, "\x1e") {
+		record = strings.Trim(record, "\n")
+		if record == "" {
+			continue
+		}
 
-	for commit := range commitsArray {
-		splitCommit := strings.Split(commitsArray[commit], "|")
-		gitCommits[commit] = GitCommit{
-			Hash:    splitCommit[0],
-			Message: strings.Join(splitCommit[1:], "|"),
+		fields := strings.SplitN(record, "\x1f", 5)
+		for len(fields) < 5 {
+			fields = append(fields, "")
 		}
+
+		gitCommits = append(gitCommits, GitCommit{
+			Hash:    fields[0],
+			Message: fields[1],
+			Author:  fields[2],
+			Merge:   len(strings.Fields(fields[3])) > 1,
+			Body:    strings.TrimSpace(fields[4]),
+		})
 	}
 
 	return gitCommits, nil
 }
 
+// CommitFiles commits only the given files with the message.
+func CommitFiles(ctx context.Context, repo, message string, files ...string) error {
+	if _, err := runGit(ctx, repo, append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+
+	_, err := runGit(ctx, repo, append([]string{"commit", "-m", message, "--"}, files...)...)
+
+	return err
+}
+
+// CreateTag creates an annotated tag on the current commit.
+func CreateTag(ctx context.Context, repo, tag, message string) error {
+	_, err := runGit(ctx, repo, "tag", "-a", tag, "-m", message)
+
+	return err
+}
+
 func GetPublicVCSURL(ctx context.Context, repo string) (string, error) {
 	origin, err := runGit(ctx, repo, "config", "--get", "remote.origin.url")
 	if err != nil {
@@ -106,3 +106,33 @@ func prepareRepository(t *testing.T, tmpDir string) {
 	runCommand(t, tmpDir, "config", "user.name", "test")
 	runCommand(t, tmpDir, "config", "user.email", "test@test.de")
 }
+
+func TestGetCommitsWithMerges(t *testing.T) {
+	tmpDir := t.TempDir()
+	prepareRepository(t, tmpDir)
+	runCommand(t, tmpDir, "checkout", "-b", "main")
+	_ = os.WriteFile(path.Join(tmpDir, "a"), []byte(""), os.ModePerm)
+	runCommand(t, tmpDir, "add", "a")
+	runCommand(t, tmpDir, "commit", "-m", "initial commit", "--no-verify", "--no-gpg-sign")
+	runCommand(t, tmpDir, "tag", "v1.0.0", "-m", "initial release")
+	runCommand(t, tmpDir, "checkout", "-b", "feature")
+	_ = os.WriteFile(path.Join(tmpDir, "b"), []byte(""), os.ModePerm)
+	runCommand(t, tmpDir, "add", "b")
+	runCommand(t, tmpDir, "commit", "-m", "wip", "--no-verify", "--no-gpg-sign")
+	runCommand(t, tmpDir, "checkout", "main")
+	runCommand(t, tmpDir, "merge", "--no-ff", "feature", "-m", "Merge pull request #1 from test/feature", "-m", "feat: new feature")
+
+	commits, err := GetCommitsWithMerges(context.Background(), tmpDir)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+	assert.True(t, commits[0].Merge)
+	assert.Equal(t, "Merge pull request #1 from test/feature", commits[0].Message)
+	assert.Equal(t, "feat: new feature", commits[0].Body)
+	assert.Equal(t, "test", commits[0].Author)
+
+	commits, err = GetCommits(context.Background(), tmpDir)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+	assert.Equal(t, "wip", commits[0].Message)
+	assert.False(t, commits[0].Merge)
+}
@@ -0,0 +1,224 @@
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+const (
+	// OnlyOnRelease runs the hook only for release builds
+	OnlyOnRelease = "release"
+	// OnlyOnDev runs the hook only for non release builds
+	OnlyOnDev = "dev"
+
+	// EnvStage contains the stage the hook is running in
+	EnvStage = "HOOK_STAGE"
+)
+
+const (
+	StageBeforeComposer = "before_composer"
+	StageAfterComposer  = "after_composer"
+	StageBeforeAssets   = "before_assets"
+	StageAfterAssets    = "after_assets"
+	StageBeforePack     = "before_pack"
+	StageAfterPack      = "after_pack"
+	StageBeforeValidate = "before_validate"
+)
+
+// Hook is a command executed at a stage of a build. In the configuration it can be a shell string or an object.
+type Hook struct {
+	// Command is executed with sh -c when no Args are given, otherwise directly.
+	// Without a shell $VAR and ${VAR} in Command and Args are expanded from the hook environment.
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args,omitempty"`
+	// WorkingDir is relative to the directory of the stage
+	WorkingDir      string            `yaml:"working_dir,omitempty"`
+	Env             map[string]string `yaml:"env,omitempty"`
+	Timeout         time.Duration     `yaml:"timeout,omitempty"`
+	ContinueOnError bool              `yaml:"continue_on_error,omitempty"`
+	OnlyOn          string            `yaml:"only_on,omitempty"`
+}
+
+// UnmarshalYAML supports the plain string hooks of older configurations.
+func (h *Hook) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*h = Hook{Command: node.Value}
+
+		return nil
+	}
+
+	type rawHook Hook
+
+	var raw rawHook
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	if raw.Command == "" {
+		return fmt.Errorf("line %d: hook requires a command", node.Line)
+	}
+
+	if raw.OnlyOn != "" && raw.OnlyOn != OnlyOnRelease && raw.OnlyOn != OnlyOnDev {
+		return fmt.Errorf("line %d: only_on must be %s or %s, got %q", node.Line, OnlyOnRelease, OnlyOnDev, raw.OnlyOn)
+	}
+
+	*h = Hook(raw)
+
+	return nil
+}
+
+// MarshalYAML writes hooks without options as plain string.
+func (h Hook) MarshalYAML() (interface{}, error) {
+	if len(h.Args) == 0 && h.WorkingDir == "" && len(h.Env) == 0 && h.Timeout == 0 && !h.ContinueOnError && h.OnlyOn == "" {
+		return h.Command, nil
+	}
+
+	type rawHook Hook
+
+	return rawHook(h), nil
+}
+
+func (h Hook) String() string {
+	if len(h.Args) == 0 {
+		return h.Command
+	}
+
+	return fmt.Sprintf("%s %s", h.Command, strings.Join(h.Args, " "))
+}
+
+// Enabled reports whether the hook runs for a release or a dev build.
+func (h Hook) Enabled(release bool) bool {
+	switch h.OnlyOn {
+	case OnlyOnRelease:
+		return release
+	case OnlyOnDev:
+		return !release
+	}
+
+	return true
+}
+
+// Context is passed to all hooks of a stage.
+type Context struct {
+	Stage   string
+	Dir     string
+	Release bool
+	Env     map[string]string
+}
+
+func (c Context) environ(hook Hook) []string {
+	env := os.Environ()
+	env = append(env, fmt.Sprintf("%s=%s", EnvStage, c.Stage))
+	env = append(env, sortedEnv(c.Env)...)
+
+	return append(env, sortedEnv(hook.Env)...)
+}
+
+// expandEnv replaces the variables in value like a shell would do, later entries of env win.
+func expandEnv(value string, env []string) string {
+	return os.Expand(value, func(key string) string {
+		found := ""
+
+		for _, entry := range env {
+			if name, v, ok := strings.Cut(entry, "="); ok && name == key {
+				found = v
+			}
+		}
+
+		return found
+	})
+}
+
+func sortedEnv(values map[string]string) []string {
+	env := make([]string, 0, len(values))
+
+	for key, value := range values {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	sort.Strings(env)
+
+	return env
+}
+
+// Run executes the hooks of a stage one after another and stops at the first failing hook.
+func Run(ctx context.Context, hooks []Hook, hookCtx Context) error {
+	for _, hook := range hooks {
+		if !hook.Enabled(hookCtx.Release) {
+			logging.FromContext(ctx).Debugf("Skipping %s hook %q, it runs only on %s", hookCtx.Stage, hook.String(), hook.OnlyOn)
+			continue
+		}
+
+		logging.FromContext(ctx).Infof("Running %s hook: %s", hookCtx.Stage, hook.String())
+
+		if err := runHook(ctx, hook, hookCtx); err != nil {
+			if hook.ContinueOnError {
+				logging.FromContext(ctx).Warnf("%s hook %q failed: %v", hookCtx.Stage, hook.String(), err)
+				continue
+			}
+
+			return fmt.Errorf("%s hook %q: %w", hookCtx.Stage, hook.String(), err)
+		}
+	}
+
+	return nil
+}
+
+func runHook(ctx context.Context, hook Hook, hookCtx Context) error {
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hook.Timeout)
+
+		defer cancel()
+	}
+
+	env := hookCtx.environ(hook)
+
+	var cmd *exec.Cmd
+	if len(hook.Args) == 0 {
+		cmd = exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	} else {
+		args := make([]string, len(hook.Args))
+		for i, arg := range hook.Args {
+			args[i] = expandEnv(arg, env)
+		}
+
+		cmd = exec.CommandContext(ctx, expandEnv(hook.Command, env), args...)
+	}
+
+	cmd.Dir = hookCtx.Dir
+	if hook.WorkingDir != "" {
+		cmd.Dir = hook.WorkingDir
+
+		if !filepath.IsAbs(hook.WorkingDir) {
+			cmd.Dir = filepath.Join(hookCtx.Dir, hook.WorkingDir)
+		}
+	}
+
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = env
+
+	if hook.Timeout > 0 {
+		killProcessGroup(cmd)
+	}
+
+	err := cmd.Run()
+
+	if hook.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", hook.Timeout)
+	}
+
+	return err
+}
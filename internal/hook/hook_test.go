@@ -0,0 +1,81 @@
+package hook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestUnmarshalHooks(t *testing.T) {
+	var hooks []Hook
+
+	err := yaml.Unmarshal([]byte(`
+- echo "plain"
+- command: npm
+  args: [run, build]
+  working_dir: src/Resources/app
+  env:
+    NODE_ENV: production
+  timeout: 30s
+  continue_on_error: true
+  only_on: release
+`), &hooks)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Hook{
+		{Command: `echo "plain"`},
+		{
+			Command:         "npm",
+			Args:            []string{"run", "build"},
+			WorkingDir:      "src/Resources/app",
+			Env:             map[string]string{"NODE_ENV": "production"},
+			Timeout:         30 * time.Second,
+			ContinueOnError: true,
+			OnlyOn:          OnlyOnRelease,
+		},
+	}, hooks)
+
+	assert.ErrorContains(t, yaml.Unmarshal([]byte(`[{args: [a]}]`), &hooks), "hook requires a command")
+	assert.ErrorContains(t, yaml.Unmarshal([]byte(`[{command: a, only_on: prod}]`), &hooks), "only_on must be")
+
+	content, err := yaml.Marshal([]Hook{{Command: "echo"}, {Command: "npm", OnlyOn: OnlyOnDev}})
+	assert.NoError(t, err)
+	assert.Equal(t, "- echo\n- command: npm\n  only_on: dev\n", string(content))
+}
+
+func TestRunHooks(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), os.ModePerm))
+
+	hooks := []Hook{
+		{Command: `echo "$HOOK_STAGE $EXTENSION_NAME $CUSTOM" > stage.txt`, Env: map[string]string{"CUSTOM": "custom"}},
+		{Command: "touch", Args: []string{"created.txt"}, WorkingDir: "sub"},
+		{Command: "touch", Args: []string{"$EXTENSION_NAME.txt", "${CUSTOM}.txt"}, Env: map[string]string{"CUSTOM": "custom"}},
+		{Command: "touch release.txt", OnlyOn: OnlyOnRelease},
+		{Command: "exit 1", ContinueOnError: true},
+	}
+
+	err := Run(context.Background(), hooks, Context{Stage: StageBeforePack, Dir: dir, Env: map[string]string{"EXTENSION_NAME": "FroshTools"}})
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "stage.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "before_pack FroshTools custom\n", string(content))
+
+	assert.FileExists(t, filepath.Join(dir, "sub", "created.txt"))
+	assert.FileExists(t, filepath.Join(dir, "FroshTools.txt"))
+	assert.FileExists(t, filepath.Join(dir, "custom.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "release.txt"))
+
+	err = Run(context.Background(), []Hook{{Command: "exit 3"}, {Command: "touch never.txt"}}, Context{Stage: StageAfterPack, Dir: dir})
+	assert.ErrorContains(t, err, `after_pack hook "exit 3"`)
+	assert.NoFileExists(t, filepath.Join(dir, "never.txt"))
+
+	err = Run(context.Background(), []Hook{{Command: "sleep 5", Timeout: 50 * time.Millisecond}}, Context{Stage: StageBeforeValidate, Dir: dir})
+	assert.ErrorContains(t, err, "timed out after 50ms")
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package hook
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup makes sure a timeout stops also the processes started by the shell.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build windows
+
+package hook
+
+import "os/exec"
+
+func killProcessGroup(_ *exec.Cmd) {}
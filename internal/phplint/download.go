@@ -7,12 +7,34 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"sync"
 
 	"github.com/haokeyingxiao/haoke-cli/internal/system"
 	"github.com/haokeyingxiao/haoke-cli/logging"
 )
 
+var (
+	downloadLocksMu sync.Mutex
+	downloadLocks   = make(map[string]*sync.Mutex)
+)
+
+// downloadLock returns the lock serializing the download of one PHP version, so concurrent lint runs share a single download.
+func downloadLock(phpVersion string) *sync.Mutex {
+	downloadLocksMu.Lock()
+	defer downloadLocksMu.Unlock()
+
+	if _, ok := downloadLocks[phpVersion]; !ok {
+		downloadLocks[phpVersion] = &sync.Mutex{}
+	}
+
+	return downloadLocks[phpVersion]
+}
+
 func findPHPWasmFile(ctx context.Context, phpVersion string) ([]byte, error) {
+	lock := downloadLock(phpVersion)
+	lock.Lock()
+	defer lock.Unlock()
+
 	expectedFile := "php-" + phpVersion + ".wasm"
 	expectedPathLocation := path.Join(system.GetShopwareCliCacheDir(), "wasm", "php", expectedFile)
 
@@ -56,9 +78,38 @@ func findPHPWasmFile(ctx context.Context, phpVersion string) ([]byte, error) {
 
 	_ = resp.Body.Close()
 
-	if err := os.WriteFile(expectedPathLocation, data, os.ModePerm); err != nil {
+	if err := writeFileAtomic(expectedPathLocation, data); err != nil {
 		logging.FromContext(ctx).Debugf("cannot write php-wasm binary to %s: %v", expectedPathLocation, err)
 	}
 
 	return data, nil
 }
+
+// writeFileAtomic writes into a temporary file next to the target and renames it, so other processes never read a partially written file.
+func writeFileAtomic(target string, data []byte) error {
+	tmp, err := os.CreateTemp(path.Dir(target), path.Base(target)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return err
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package phplint
 import (
 	"context"
 	"os"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,3 +17,18 @@ func TestDownloadPHPFile(t *testing.T) {
 	_, err := findPHPWasmFile(context.Background(), "7.4")
 	assert.NoError(t, err)
 }
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	target := path.Join(dir, "php-8.2.wasm")
+
+	assert.NoError(t, writeFileAtomic(target, []byte("wasm")))
+
+	content, err := os.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "wasm", string(content))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
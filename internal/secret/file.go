@@ -0,0 +1,198 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	keyIterations = 100_000
+	keyLength     = 32
+	saltLength    = 16
+)
+
+type fileContent struct {
+	Salt  []byte `json:"salt,omitempty"`
+	Nonce []byte `json:"nonce,omitempty"`
+	// Data holds the encrypted secrets when a passphrase is used
+	Data    []byte            `json:"data,omitempty"`
+	Secrets map[string]string `json:"secrets,omitempty"`
+}
+
+type fileStore struct {
+	mu         sync.Mutex
+	path       string
+	passphrase string
+}
+
+// NewFileStore returns a store writing into a file only readable by the current user, with a passphrase the file is encrypted.
+func NewFileStore(path, passphrase string) Store {
+	return &fileStore{path: path, passphrase: passphrase}
+}
+
+func (s *fileStore) Name() string {
+	if s.passphrase != "" {
+		return "encrypted file"
+	}
+
+	return "file"
+}
+
+func (s *fileStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, _, err := s.read()
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secrets[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+func (s *fileStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, salt, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	secrets[key] = value
+
+	return s.write(secrets, salt)
+}
+
+func (s *fileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, salt, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := secrets[key]; !ok {
+		return nil
+	}
+
+	delete(secrets, key)
+
+	return s.write(secrets, salt)
+}
+
+func (s *fileStore) read() (map[string]string, []byte, error) {
+	secrets := make(map[string]string)
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return secrets, nil, nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var content fileContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, nil, fmt.Errorf("cannot read secret file %s: %w", s.path, err)
+	}
+
+	if content.Data == nil {
+		for key, value := range content.Secrets {
+			secrets[key] = value
+		}
+
+		return secrets, nil, nil
+	}
+
+	if s.passphrase == "" {
+		return nil, nil, fmt.Errorf("secret file %s is encrypted, set %s", s.path, EnvPassphrase)
+	}
+
+	gcm, err := newCipher(s.passphrase, content.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plain, err := gcm.Open(nil, content.Nonce, content.Data, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot decrypt secret file %s, check %s", s.path, EnvPassphrase)
+	}
+
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, nil, err
+	}
+
+	return secrets, content.Salt, nil
+}
+
+func (s *fileStore) write(secrets map[string]string, salt []byte) error {
+	content := fileContent{Secrets: secrets}
+
+	if s.passphrase != "" {
+		if salt == nil {
+			salt = make([]byte, saltLength)
+			if _, err := rand.Read(salt); err != nil {
+				return err
+			}
+		}
+
+		gcm, err := newCipher(s.passphrase, salt)
+		if err != nil {
+			return err
+		}
+
+		plain, err := json.Marshal(secrets)
+		if err != nil {
+			return err
+		}
+
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return err
+		}
+
+		content = fileContent{Salt: salt, Nonce: nonce, Data: gcm.Seal(nil, nonce, plain, nil)}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(s.path, data, 0o600); err != nil {
+		return err
+	}
+
+	// WriteFile keeps the permissions of an existing file
+	return os.Chmod(s.path, 0o600)
+}
+
+func newCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, keyIterations, keyLength, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,57 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secrets.json")
+	store := NewFileStore(file, "")
+
+	_, err := store.Get("token")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.NoError(t, store.Set("token", "abc"))
+
+	value, err := store.Get("token")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", value)
+
+	stat, err := os.Stat(file)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
+
+	assert.NoError(t, store.Delete("token"))
+	assert.NoError(t, store.Delete("token"))
+
+	_, err = store.Get("token")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestEncryptedFileStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "secrets.json")
+
+	assert.NoError(t, NewFileStore(file, "").Set("password", "plain"))
+
+	store := NewFileStore(file, "passphrase")
+	assert.NoError(t, store.Set("token", "s3cr3t"))
+
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "s3cr3t")
+	assert.NotContains(t, string(content), "plain")
+
+	value, err := store.Get("password")
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", value)
+
+	_, err = NewFileStore(file, "wrong").Get("token")
+	assert.ErrorContains(t, err, "cannot decrypt")
+
+	_, err = NewFileStore(file, "").Get("token")
+	assert.ErrorContains(t, err, EnvPassphrase)
+}
@@ -0,0 +1,127 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keyringStore uses the Secret Service on Linux and the Keychain on macOS through their command line tools.
+type keyringStore struct {
+	binary string
+}
+
+func newKeyringStore() Store {
+	switch runtime.GOOS {
+	case "linux":
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return nil
+		}
+
+		if binary, err := exec.LookPath("secret-tool"); err == nil {
+			return &keyringStore{binary: binary}
+		}
+	case "darwin":
+		if binary, err := exec.LookPath("security"); err == nil {
+			return &keyringStore{binary: binary}
+		}
+	}
+
+	return nil
+}
+
+func (s *keyringStore) Name() string {
+	return BackendKeyring
+}
+
+func (s *keyringStore) Get(key string) (string, error) {
+	var cmd *exec.Cmd
+
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command(s.binary, "find-generic-password", "-s", serviceName, "-a", key, "-w") //nolint:gosec
+	} else {
+		cmd = exec.Command(s.binary, "lookup", "service", serviceName, "account", key) //nolint:gosec
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && keyringNotFound(runtime.GOOS, exitErr.ExitCode(), out, stderr.Bytes()) {
+		return "", ErrNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("cannot read from keyring: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	if runtime.GOOS != "darwin" {
+		return string(out), nil
+	}
+
+	// the keychain only receives base64, so secrets do not need escaping in the interactive mode
+	value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", fmt.Errorf("cannot read from keyring: %w", err)
+	}
+
+	return string(value), nil
+}
+
+// keyringNotFound reports whether the tool exited because the secret does not exist.
+// Other failures like a locked keyring or a denied prompt must not look like missing credentials.
+func keyringNotFound(goos string, exitCode int, stdout, stderr []byte) bool {
+	if goos == "darwin" {
+		// errSecItemNotFound
+		return exitCode == 44
+	}
+
+	// secret-tool exits silently with 1 when nothing matches
+	return exitCode == 1 && len(bytes.TrimSpace(stdout)) == 0 && len(bytes.TrimSpace(stderr)) == 0
+}
+
+func (s *keyringStore) Set(key, value string) error {
+	var cmd *exec.Cmd
+
+	if runtime.GOOS == "darwin" {
+		// the interactive mode keeps the secret out of the process list
+		cmd = exec.Command(s.binary, "-i") //nolint:gosec
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", serviceName, key, base64.StdEncoding.EncodeToString([]byte(value))))
+	} else {
+		cmd = exec.Command(s.binary, "store", "--label", fmt.Sprintf("%s %s", serviceName, key), "service", serviceName, "account", key) //nolint:gosec
+		cmd.Stdin = strings.NewReader(value)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cannot write to keyring: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+func (s *keyringStore) Delete(key string) error {
+	var cmd *exec.Cmd
+
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command(s.binary, "delete-generic-password", "-s", serviceName, "-a", key) //nolint:gosec
+	} else {
+		cmd = exec.Command(s.binary, "clear", "service", serviceName, "account", key) //nolint:gosec
+	}
+
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); err != nil && !errors.As(err, &exitErr) {
+		return fmt.Errorf("cannot delete from keyring: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package secret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyringNotFound(t *testing.T) {
+	assert.True(t, keyringNotFound("linux", 1, nil, nil))
+	assert.False(t, keyringNotFound("linux", 1, nil, []byte("Cannot autolaunch D-Bus without X11 $DISPLAY")))
+	assert.False(t, keyringNotFound("linux", 2, nil, nil))
+
+	assert.True(t, keyringNotFound("darwin", 44, nil, []byte("The specified item could not be found in the keychain.")))
+	assert.False(t, keyringNotFound("darwin", 51, nil, []byte("User interaction is not allowed.")))
+}
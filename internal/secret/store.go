@@ -0,0 +1,61 @@
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNotFound is returned when no secret exists for the key.
+var ErrNotFound = errors.New("secret not found")
+
+const (
+	BackendKeyring = "keyring"
+	BackendFile    = "file"
+
+	// EnvBackend selects the backend, by default the keyring is used when available.
+	EnvBackend = "HAOKE_CLI_SECRET_STORE"
+	// EnvPassphrase encrypts the file backend with the given passphrase.
+	EnvPassphrase = "HAOKE_CLI_SECRET_PASSPHRASE"
+
+	serviceName = "haoke-cli"
+	fileName    = ".haoke-cli-secrets.json"
+)
+
+// Store persists credentials and tokens outside of the config file.
+type Store interface {
+	Name() string
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+var Default = sync.OnceValues(New)
+
+// New returns the store selected by HAOKE_CLI_SECRET_STORE, the OS keyring is preferred with the file store as fallback.
+func New() (Store, error) {
+	backend := os.Getenv(EnvBackend)
+
+	switch backend {
+	case "", BackendKeyring:
+		if keyring := newKeyringStore(); keyring != nil {
+			return keyring, nil
+		}
+
+		if backend == BackendKeyring {
+			return nil, fmt.Errorf("no keyring is available on this system")
+		}
+	case BackendFile:
+	default:
+		return nil, fmt.Errorf("unknown secret store %q, use %s or %s", backend, BackendKeyring, BackendFile)
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileStore(filepath.Join(configDir, fileName), os.Getenv(EnvPassphrase)), nil
+}
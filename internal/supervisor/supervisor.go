@@ -0,0 +1,243 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/haokeyingxiao/haoke-cli/logging"
+)
+
+const (
+	defaultMinBackoff   = time.Second
+	defaultMaxBackoff   = time.Minute
+	defaultStableAfter  = time.Minute
+	defaultDrainTimeout = 30 * time.Second
+)
+
+// Process describes a long-running command which should be kept alive by the supervisor.
+type Process struct {
+	Name string
+	// Command creates a fresh command for every start of the process.
+	Command func(ctx context.Context) *exec.Cmd
+}
+
+// Status is a snapshot of a supervised process. UptimeSeconds is zero while the process is stopped.
+type Status struct {
+	Name          string     `json:"name"`
+	Running       bool       `json:"running"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	UptimeSeconds int64      `json:"uptime_seconds"`
+	Restarts      int        `json:"restarts"`
+	LastExitCode  int        `json:"last_exit_code"`
+	LastExitAt    *time.Time `json:"last_exit_at,omitempty"`
+}
+
+type Supervisor struct {
+	// MinBackoff is the delay before the first restart of a failing process.
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponential restart delay.
+	MaxBackoff time.Duration
+	// StableAfter resets the backoff when a process was running at least this long.
+	StableAfter time.Duration
+	// DrainTimeout is the time processes get to finish after SIGTERM before they are killed.
+	DrainTimeout time.Duration
+
+	processes []Process
+	mu        sync.Mutex
+	statuses  map[string]*Status
+}
+
+func New(processes ...Process) *Supervisor {
+	statuses := make(map[string]*Status, len(processes))
+
+	for _, p := range processes {
+		statuses[p.Name] = &Status{Name: p.Name, LastExitCode: -1}
+	}
+
+	return &Supervisor{
+		MinBackoff:   defaultMinBackoff,
+		MaxBackoff:   defaultMaxBackoff,
+		StableAfter:  defaultStableAfter,
+		DrainTimeout: defaultDrainTimeout,
+		processes:    processes,
+		statuses:     statuses,
+	}
+}
+
+// Run starts all processes and restarts them when they exit until the context is cancelled.
+// On cancellation all processes receive SIGTERM and Run waits until they have exited.
+func (s *Supervisor) Run(ctx context.Context) error {
+	if len(s.processes) == 0 {
+		return errors.New("no processes to supervise")
+	}
+
+	seen := make(map[string]bool, len(s.processes))
+
+	for _, p := range s.processes {
+		if seen[p.Name] {
+			return fmt.Errorf("duplicate process name %q", p.Name)
+		}
+
+		seen[p.Name] = true
+	}
+
+	var wg sync.WaitGroup
+
+	for _, p := range s.processes {
+		wg.Add(1)
+
+		go func(p Process) {
+			defer wg.Done()
+			s.supervise(ctx, p)
+		}(p)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (s *Supervisor) supervise(ctx context.Context, p Process) {
+	backoff := time.Duration(0)
+
+	for {
+		cmd := p.Command(ctx)
+		cmd.Cancel = func() error {
+			if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+				return cmd.Process.Kill()
+			}
+
+			return nil
+		}
+		cmd.WaitDelay = s.DrainTimeout
+
+		startedAt := time.Now()
+		s.update(p.Name, func(st *Status) {
+			st.Running = true
+			st.StartedAt = &startedAt
+		})
+
+		err := cmd.Run()
+		exitCode := exitCodeOf(cmd, err)
+		ranFor := time.Since(startedAt)
+
+		s.update(p.Name, func(st *Status) {
+			st.Running = false
+			st.LastExitCode = exitCode
+			exitedAt := time.Now()
+			st.LastExitAt = &exitedAt
+		})
+
+		if ctx.Err() != nil {
+			logging.FromContext(ctx).Infof("%s stopped (exit code %d)", p.Name, exitCode)
+			return
+		}
+
+		if exitCode == 0 || ranFor >= s.StableAfter {
+			backoff = 0
+		} else {
+			backoff = s.nextBackoff(backoff)
+		}
+
+		restarts := 0
+		s.update(p.Name, func(st *Status) {
+			st.Restarts++
+			restarts = st.Restarts
+		})
+
+		if err != nil {
+			logging.FromContext(ctx).Warnf("%s exited after %s with code %d: %v, restarting in %s (restart %d)", p.Name, ranFor.Round(time.Second), exitCode, err, backoff, restarts)
+		} else {
+			logging.FromContext(ctx).Debugf("%s exited after %s, restarting (restart %d)", p.Name, ranFor.Round(time.Second), restarts)
+		}
+
+		if backoff == 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (s *Supervisor) nextBackoff(current time.Duration) time.Duration {
+	if current == 0 {
+		return s.MinBackoff
+	}
+
+	next := current * 2
+	if next > s.MaxBackoff {
+		return s.MaxBackoff
+	}
+
+	return next
+}
+
+func (s *Supervisor) update(name string, fn func(st *Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(s.statuses[name])
+}
+
+// Statuses returns a snapshot of all supervised processes in the order they were registered.
+func (s *Supervisor) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Status, 0, len(s.processes))
+
+	for _, p := range s.processes {
+		st := *s.statuses[p.Name]
+
+		if st.Running && st.StartedAt != nil {
+			st.UptimeSeconds = int64(time.Since(*st.StartedAt).Seconds())
+		}
+
+		result = append(result, st)
+	}
+
+	return result
+}
+
+// Handler serves the current statuses as JSON.
+func (s *Supervisor) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(s.Statuses()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func exitCodeOf(cmd *exec.Cmd, err error) int {
+	if cmd.ProcessState != nil {
+		return cmd.ProcessState.ExitCode()
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	if err != nil {
+		return -1
+	}
+
+	return 0
+}
+
+// TerminationSignals are the signals which should trigger a graceful drain of the supervised processes.
+var TerminationSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
@@ -0,0 +1,94 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("SUPERVISOR_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	os.Exit(3)
+}
+
+func helperCommand(ctx context.Context) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=TestHelperProcess")
+	cmd.Env = append(os.Environ(), "SUPERVISOR_HELPER_PROCESS=1")
+
+	return cmd
+}
+
+func TestNextBackoff(t *testing.T) {
+	s := New()
+	s.MinBackoff = time.Second
+	s.MaxBackoff = 5 * time.Second
+
+	assert.Equal(t, time.Second, s.nextBackoff(0))
+	assert.Equal(t, 2*time.Second, s.nextBackoff(time.Second))
+	assert.Equal(t, 4*time.Second, s.nextBackoff(2*time.Second))
+	assert.Equal(t, 5*time.Second, s.nextBackoff(4*time.Second))
+	assert.Equal(t, 5*time.Second, s.nextBackoff(5*time.Second))
+}
+
+func TestRunWithoutProcesses(t *testing.T) {
+	assert.Error(t, New().Run(context.Background()))
+}
+
+func TestRunRejectsDuplicateNames(t *testing.T) {
+	s := New(Process{Name: "a", Command: helperCommand}, Process{Name: "a", Command: helperCommand})
+
+	assert.ErrorContains(t, s.Run(context.Background()), "duplicate process name")
+}
+
+func TestRunRestartsFailingProcess(t *testing.T) {
+	s := New(Process{Name: "failing", Command: helperCommand})
+	s.MinBackoff = 10 * time.Millisecond
+	s.MaxBackoff = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		assert.NoError(t, s.Run(ctx))
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return s.Statuses()[0].Restarts >= 2
+	}, 10*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+
+	statuses := s.Statuses()
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, "failing", statuses[0].Name)
+	assert.Equal(t, 3, statuses[0].LastExitCode)
+	assert.False(t, statuses[0].Running)
+	assert.NotNil(t, statuses[0].LastExitAt)
+}
+
+func TestHandler(t *testing.T) {
+	s := New(Process{Name: "a", Command: helperCommand}, Process{Name: "b", Command: helperCommand})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	var statuses []Status
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &statuses))
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "a", statuses[0].Name)
+	assert.Equal(t, -1, statuses[0].LastExitCode)
+	assert.Nil(t, statuses[0].LastExitAt)
+	assert.NotContains(t, rec.Body.String(), "last_exit_at")
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+}
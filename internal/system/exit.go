@@ -0,0 +1,15 @@
+package system
+
+// ExitError ends the cli with the given exit code instead of the default one.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
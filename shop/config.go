@@ -11,15 +11,18 @@ import (
 	"github.com/google/uuid"
 	adminSdk "github.com/haokeyingxiao/go-haoke-admin-api-sdk"
 	"gopkg.in/yaml.v3"
+
+	"github.com/haokeyingxiao/haoke-cli/internal/hook"
 )
 
 type Config struct {
-	AdditionalConfigs []string        `yaml:"include,omitempty"`
-	URL               string          `yaml:"url"`
-	Build             *ConfigBuild    `yaml:"build,omitempty"`
-	AdminApi          *ConfigAdminApi `yaml:"admin_api,omitempty"`
-	ConfigDump        *ConfigDump     `yaml:"dump,omitempty"`
-	Sync              *ConfigSync     `yaml:"sync,omitempty"`
+	AdditionalConfigs []string                   `yaml:"include,omitempty"`
+	URL               string                     `yaml:"url"`
+	Build             *ConfigBuild               `yaml:"build,omitempty"`
+	AdminApi          *ConfigAdminApi            `yaml:"admin_api,omitempty"`
+	ConfigDump        *ConfigDump                `yaml:"dump,omitempty"`
+	Sync              *ConfigSync                `yaml:"sync,omitempty"`
+	Extensions        map[string]ConfigExtension `yaml:"extensions,omitempty"`
 	foundConfig       bool
 }
 
@@ -31,6 +34,41 @@ type ConfigBuild struct {
 	CleanupPaths          []string `yaml:"cleanup_paths,omitempty"`
 	Browserslist          string   `yaml:"browserslist,omitempty"`
 	ExcludeExtensions     []string `yaml:"exclude_extensions,omitempty"`
+	// SkipStages are stages of project ci which are not executed
+	SkipStages []string         `yaml:"skip_stages,omitempty"`
+	Hooks      ConfigBuildHooks `yaml:"hooks,omitempty"`
+}
+
+// ConfigBuildHooks are the hooks of the project ci stages.
+type ConfigBuildHooks struct {
+	Composer     ConfigBuildStageHooks `yaml:"composer,omitempty"`
+	Assets       ConfigBuildStageHooks `yaml:"assets,omitempty"`
+	Cleanup      ConfigBuildStageHooks `yaml:"cleanup,omitempty"`
+	Warmup       ConfigBuildStageHooks `yaml:"warmup,omitempty"`
+	AssetInstall ConfigBuildStageHooks `yaml:"asset_install,omitempty"`
+}
+
+type ConfigBuildStageHooks struct {
+	Before []hook.Hook `yaml:"before,omitempty"`
+	After  []hook.Hook `yaml:"after,omitempty"`
+}
+
+// Stage returns the hooks of the stage with the name of its yaml key.
+func (h ConfigBuildHooks) Stage(name string) ConfigBuildStageHooks {
+	switch name {
+	case "composer":
+		return h.Composer
+	case "assets":
+		return h.Assets
+	case "cleanup":
+		return h.Cleanup
+	case "warmup":
+		return h.Warmup
+	case "asset_install":
+		return h.AssetInstall
+	}
+
+	return ConfigBuildStageHooks{}
 }
 
 type ConfigAdminApi struct {
@@ -41,6 +79,13 @@ type ConfigAdminApi struct {
 	DisableSSLCheck bool   `yaml:"disable_ssl_check,omitempty"`
 }
 
+// ConfigExtension describes the desired state of an extension, unset fields keep the current state.
+type ConfigExtension struct {
+	Installed *bool  `yaml:"installed,omitempty"`
+	Active    *bool  `yaml:"active,omitempty"`
+	Version   string `yaml:"version,omitempty"`
+}
+
 type ConfigDump struct {
 	Rewrite map[string]core.Rewrite `yaml:"rewrite,omitempty"`
 	NoData  []string                `yaml:"nodata,omitempty"`